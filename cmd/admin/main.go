@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+)
+
+// Usage: go run cmd/admin/main.go -email satrio@test.test [-role admin]
+func main() {
+	email := flag.String("email", "", "email of the user to update")
+	role := flag.String("role", usecase.RoleAdmin, "role to assign (admin or customer)")
+	flag.Parse()
+
+	if *email == "" {
+		log.Fatal("-email is required")
+	}
+	if *role != usecase.RoleAdmin && *role != usecase.RoleCustomer {
+		log.Fatalf("Invalid role %q: must be %q or %q", *role, usecase.RoleAdmin, usecase.RoleCustomer)
+	}
+
+	cfg := config.LoadConfig()
+
+	db, err := postgresql.NewDatabase(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	userRepo := postgresql.NewPostgresUserRepository(db, time.Duration(cfg.Database.Timeout)*time.Second)
+
+	user, err := userRepo.GetByEmail(ctx, *email)
+	if err != nil {
+		log.Fatalf("Failed to look up user: %v", err)
+	}
+	if user == nil {
+		log.Fatalf("No user found with email %s", *email)
+	}
+
+	if err := userRepo.UpdateRole(ctx, user.ID, *role); err != nil {
+		log.Fatalf("Failed to update role: %v", err)
+	}
+
+	log.Printf("User %s is now %s. Existing tokens keep their old role until they expire.", *email, *role)
+}
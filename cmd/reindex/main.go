@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"time"
+
+	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+	"github.com/masatrio/bookstore-api/internal/search"
+)
+
+// Rebuilds the embedded search index snapshot from the database. Restart the app afterwards so
+// that it loads the new snapshot.
+//
+// Usage: go run cmd/reindex/main.go [-path search_index.gob]
+func main() {
+	cfg := config.LoadConfig()
+
+	path := flag.String("path", cfg.Search.IndexPath, "snapshot file to write (defaults to SEARCH_INDEX_PATH)")
+	flag.Parse()
+
+	if *path == "" {
+		log.Fatal("-path or SEARCH_INDEX_PATH is required")
+	}
+
+	db, err := postgresql.NewDatabase(cfg.Database)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+
+	books := postgresql.NewPostgresBookRepository(db, time.Duration(cfg.Database.Timeout)*time.Second)
+
+	count, err := search.Reindex(context.Background(), books, search.NewIndex(*path))
+	if err != nil {
+		log.Fatalf("Reindex failed: %v", err)
+	}
+
+	log.Printf("Indexed %d books into %s.", count, *path)
+}
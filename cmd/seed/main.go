@@ -24,6 +24,9 @@ func main() {
 	log.Println("Seeding completed successfully.")
 }
 
+// defaultStock is the number of copies seeded for every book.
+const defaultStock = 100
+
 func seed(db *sql.DB) error {
 	users := []struct {
 		Name     string
@@ -77,10 +80,20 @@ func seed(db *sql.DB) error {
 	}
 
 	for _, book := range books {
-		_, err := db.Exec("INSERT INTO books (title, author, price) VALUES ($1, $2, $3)", book.Title, book.Author, book.Price)
+		var bookID, authorID int64
+		err := db.QueryRow("INSERT INTO books (title, author, price, stock) VALUES ($1, $2, $3, $4) RETURNING id", book.Title, book.Author, book.Price, defaultStock).Scan(&bookID)
 		if err != nil {
 			return err
 		}
+
+		// Every seeded book has a single author, credited under the same name as its credit line.
+		err = db.QueryRow("INSERT INTO authors (name) VALUES ($1) ON CONFLICT (name_key) DO UPDATE SET name = authors.name RETURNING id", book.Author).Scan(&authorID)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec("INSERT INTO book_authors (book_id, author_id, role) VALUES ($1, $2, 'author')", bookID, authorID); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -3,7 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,9 +12,13 @@ import (
 
 	"github.com/masatrio/bookstore-api/config" // Import the config package
 	handler "github.com/masatrio/bookstore-api/internal/delivery/http"
+	"github.com/masatrio/bookstore-api/internal/health"
 	"github.com/masatrio/bookstore-api/utils"
 )
 
+// healthCheckTimeout bounds each dependency check of the readiness probe.
+const healthCheckTimeout = 2 * time.Second
+
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -22,14 +26,48 @@ func main() {
 	// Load the application configuration
 	cfg := config.LoadConfig()
 
-	tracer := utils.NewTracer(ctx, cfg.Server.ServiceName)
+	logger, err := utils.NewLogger(os.Stdout, cfg.Server.LogLevel)
+	if err != nil {
+		panic("Invalid LOG_LEVEL environment variable: " + err.Error())
+	}
+	slog.SetDefault(logger)
+
+	tracer, shutdownTracer, err := utils.NewTracer(ctx, cfg.Server.ServiceName, cfg.Server.Tracing, cfg.Tracing)
+	if err != nil {
+		logger.Error("Failed to create tracer", slog.Any("error", err))
+		os.Exit(1)
+	}
 
-	router := handler.InitAPP(cfg, tracer)
-	ServeHTTP(router, *cfg)
+	meterProvider, metricsHandler, err := utils.NewMeterProvider(ctx, cfg.Server.ServiceName, cfg.Metrics, cfg.Tracing)
+	if err != nil {
+		logger.Error("Failed to create meter provider", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	registry := health.NewRegistry(healthCheckTimeout)
+	if cfg.Server.Tracing {
+		registry.RegisterOptional("otlp_traces", health.NewTCPChecker(cfg.Tracing.Endpoint))
+	}
+	if cfg.Metrics.Exporter == "otlp" {
+		registry.RegisterOptional("otlp_metrics", health.NewTCPChecker(cfg.Metrics.OTLPEndpoint))
+	}
+
+	router := handler.InitAPP(cfg, tracer, logger, metricsHandler, registry)
+	ServeHTTP(router, *cfg, logger, registry)
+
+	// Flush spans and metrics that are still buffered.
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := shutdownTracer(flushCtx); err != nil {
+		logger.Error("Failed to flush traces", slog.Any("error", err))
+	}
+	if err := meterProvider.Shutdown(flushCtx); err != nil {
+		logger.Error("Failed to flush metrics", slog.Any("error", err))
+	}
 }
 
 // ServeHTTP serve HTTP API gracefully
-func ServeHTTP(router http.Handler, config config.Config) {
+func ServeHTTP(router http.Handler, config config.Config, logger *slog.Logger, registry *health.Registry) {
 	srv := &http.Server{
 		Addr:         toPort(config.Server.Port),
 		Handler:      router,
@@ -42,24 +80,30 @@ func ServeHTTP(router http.Handler, config config.Config) {
 	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
-		log.Printf("Starting server on %s\n", srv.Addr)
+		logger.Info("Starting server", slog.String("addr", srv.Addr))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Failed to start server: %v", err)
+			logger.Error("Failed to start server", slog.Any("error", err))
+			os.Exit(1)
 		}
 	}()
 
 	// Wait for shutdown signal
 	<-stop
-	log.Println("Shutting down server...")
+	logger.Info("Shutting down server...")
+
+	// Report not ready first and keep serving while load balancers notice.
+	registry.MarkShuttingDown()
+	time.Sleep(time.Duration(config.Server.ShutdownDelay) * time.Second)
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		log.Fatalf("Server shutdown failed: %v", err)
+		logger.Error("Server shutdown failed", slog.Any("error", err))
+		os.Exit(1)
 	}
 
-	log.Println("Server stopped gracefully")
+	logger.Info("Server stopped gracefully")
 }
 
 func toPort(port int) string {
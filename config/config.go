@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"log"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/joho/godotenv"
@@ -17,11 +20,32 @@ type ServerConfig struct {
 	ReadTimeout  int // in seconds
 	WriteTimeout int // in seconds
 	IdleTimeout  int // in seconds
+	// RequestTimeout is the deadline given to each request's context. Keep it below WriteTimeout
+	// so that a timed-out request still gets its error response. In seconds, 0 disables it.
+	RequestTimeout int
+	// ShutdownDelay is how long the server keeps serving after reporting not ready, so that
+	// load balancers can stop routing to it before connections are closed. In seconds.
+	ShutdownDelay int
+	// MaxPageLimit caps the limit accepted by listing endpoints.
+	MaxPageLimit int
+	// TrustedProxies are the addresses of reverse proxies whose X-Forwarded-For and X-Real-IP
+	// headers are believed. Empty trusts no one and uses the peer address.
+	TrustedProxies []netip.Prefix
+}
+
+type TracingConfig struct {
+	Endpoint    string // host:port of the OTLP receiver
+	Protocol    string // "grpc" or "http"
+	Headers     map[string]string
+	Insecure    bool   // send spans without TLS
+	CAFile      string // CA bundle used to verify the receiver, defaults to the system roots
+	SampleRatio float64
 }
 
 type JWTConfig struct {
-	Secret string
-	Expiry int
+	Secret        string
+	Expiry        int
+	RefreshExpiry int // in hours
 }
 
 type DatabaseConfig struct {
@@ -38,11 +62,65 @@ type RedisConfig struct {
 	DB       int
 }
 
+type CacheConfig struct {
+	Driver string // "redis" or "memory"
+	TTL    int    // in seconds
+}
+
+type MailConfig struct {
+	Driver       string // "smtp", "file" or "stdout"
+	From         string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	FilePath     string
+}
+
+type SearchConfig struct {
+	Driver string // "postgres" or "memory"
+	// IndexPath is where the memory driver keeps its snapshot. Empty keeps the index in memory
+	// only, so it is rebuilt from the database on every start.
+	IndexPath string
+}
+
+type MetricsConfig struct {
+	Exporter     string // "prometheus" or "otlp"
+	OTLPEndpoint string
+}
+
+// RateLimit is a token bucket that allows bursts of up to Burst requests and refills at Rate
+// requests per second.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	Default RateLimit
+	Routes  map[string]RateLimit // overrides keyed by route name, e.g. "books.list"
+}
+
+// ForRoute returns the limit for the named route, falling back to the default.
+func (c RateLimitConfig) ForRoute(route string) RateLimit {
+	if limit, ok := c.Routes[route]; ok {
+		return limit
+	}
+	return c.Default
+}
+
 type Config struct {
-	Server   ServerConfig
-	JWT      JWTConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
+	Server    ServerConfig
+	Tracing   TracingConfig
+	JWT       JWTConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	Cache     CacheConfig
+	Mail      MailConfig
+	Search    SearchConfig
+	RateLimit RateLimitConfig
+	Metrics   MetricsConfig
 }
 
 var cfg *Config
@@ -86,10 +164,56 @@ func LoadConfig() *Config {
 			tracing = false
 		}
 
+		// Load tracing exporter config
+		tracingEndpoint := os.Getenv("TRACING_OTLP_ENDPOINT")
+		if tracingEndpoint == "" {
+			tracingEndpoint = "otel-collector:4317"
+		}
+
+		tracingProtocol := os.Getenv("TRACING_OTLP_PROTOCOL")
+		if tracingProtocol == "" {
+			tracingProtocol = "grpc"
+		}
+		if tracingProtocol != "grpc" && tracingProtocol != "http" {
+			panic("Invalid TRACING_OTLP_PROTOCOL environment variable: must be grpc or http")
+		}
+
+		tracingHeaders, err := parseKeyValues(os.Getenv("TRACING_OTLP_HEADERS"))
+		if err != nil {
+			panic("Invalid TRACING_OTLP_HEADERS environment variable: " + err.Error())
+		}
+
+		tracingInsecure := true
+		if tracingInsecureStr := os.Getenv("TRACING_OTLP_INSECURE"); tracingInsecureStr != "" {
+			tracingInsecure, err = strconv.ParseBool(tracingInsecureStr)
+			if err != nil {
+				panic("Invalid TRACING_OTLP_INSECURE environment variable: must be a boolean")
+			}
+		}
+
+		tracingSampleRatio := 1.0
+		if ratioStr := os.Getenv("TRACING_SAMPLE_RATIO"); ratioStr != "" {
+			tracingSampleRatio, err = strconv.ParseFloat(ratioStr, 64)
+			if err != nil || tracingSampleRatio < 0 || tracingSampleRatio > 1 {
+				panic("Invalid TRACING_SAMPLE_RATIO environment variable: must be between 0 and 1")
+			}
+		}
+
 		// Load read, write, and idle timeouts from environment variables
 		readTimeout := getEnvAsInt("SERVER_READ_TIMEOUT", 5)
 		writeTimeout := getEnvAsInt("SERVER_WRITE_TIMEOUT", 10)
 		idleTimeout := getEnvAsInt("SERVER_IDLE_TIMEOUT", 60)
+		requestTimeout := getEnvAsInt("SERVER_REQUEST_TIMEOUT", 8)
+		shutdownDelay := getEnvAsInt("SERVER_SHUTDOWN_DELAY", 5)
+		maxPageLimit := getEnvAsInt("SERVER_MAX_PAGE_LIMIT", 100)
+		if maxPageLimit < 1 {
+			panic("Invalid SERVER_MAX_PAGE_LIMIT environment variable: must be at least 1")
+		}
+
+		trustedProxies, err := parseTrustedProxies(os.Getenv("SERVER_TRUSTED_PROXIES"))
+		if err != nil {
+			panic("Invalid SERVER_TRUSTED_PROXIES environment variable: " + err.Error())
+		}
 
 		// Load JWT config
 		jwtSecret := os.Getenv("JWT_SECRET")
@@ -107,6 +231,8 @@ func LoadConfig() *Config {
 			panic("Invalid JWT_EXPIRY environment variable: must be an integer")
 		}
 
+		jwtRefreshExpiry := getEnvAsInt("JWT_REFRESH_EXPIRY", 720)
+
 		// Load database config
 		databaseURL := os.Getenv("DATABASE_URL")
 		if databaseURL == "" {
@@ -155,9 +281,20 @@ func LoadConfig() *Config {
 			}
 		}
 
+		// Load cache config
+		cacheDriver := os.Getenv("CACHE_DRIVER")
+		if cacheDriver == "" {
+			cacheDriver = "redis"
+		}
+		if cacheDriver != "redis" && cacheDriver != "memory" {
+			panic("Invalid CACHE_DRIVER environment variable: must be redis or memory")
+		}
+
+		cacheTTL := getEnvAsInt("CACHE_TTL", 300)
+
 		// Load Redis config
 		redisURL := os.Getenv("REDIS_URL")
-		if redisURL == "" {
+		if redisURL == "" && cacheDriver == "redis" {
 			panic("REDIS_URL environment variable is not set")
 		}
 
@@ -173,19 +310,105 @@ func LoadConfig() *Config {
 			}
 		}
 
+		// Load search config
+		searchDriver := os.Getenv("SEARCH_DRIVER")
+		if searchDriver == "" {
+			searchDriver = "postgres"
+		}
+		if searchDriver != "postgres" && searchDriver != "memory" {
+			panic("Invalid SEARCH_DRIVER environment variable: must be postgres or memory")
+		}
+
+		// Load mail config
+		mailDriver := os.Getenv("MAIL_DRIVER")
+		if mailDriver == "" {
+			mailDriver = "stdout"
+		}
+		if mailDriver != "smtp" && mailDriver != "file" && mailDriver != "stdout" {
+			panic("Invalid MAIL_DRIVER environment variable: must be smtp, file or stdout")
+		}
+
+		mailFrom := os.Getenv("MAIL_FROM")
+		if mailFrom == "" {
+			mailFrom = "no-reply@bookstore.local"
+		}
+
+		smtpHost := os.Getenv("SMTP_HOST")
+		if smtpHost == "" && mailDriver == "smtp" {
+			panic("SMTP_HOST environment variable is not set")
+		}
+
+		mailFilePath := os.Getenv("MAIL_FILE_PATH")
+		if mailFilePath == "" {
+			mailFilePath = "mail.log"
+		}
+
+		// Load rate limit config
+		rateLimitEnabled := true
+		if rateLimitEnabledStr := os.Getenv("RATE_LIMIT_ENABLED"); rateLimitEnabledStr != "" {
+			rateLimitEnabled, err = strconv.ParseBool(rateLimitEnabledStr)
+			if err != nil {
+				panic("Invalid RATE_LIMIT_ENABLED environment variable: must be a boolean")
+			}
+		}
+
+		defaultRateLimit := RateLimit{Rate: 10, Burst: 20}
+		if rateStr := os.Getenv("RATE_LIMIT_RATE"); rateStr != "" {
+			defaultRateLimit.Rate, err = strconv.ParseFloat(rateStr, 64)
+			if err != nil || defaultRateLimit.Rate <= 0 {
+				panic("Invalid RATE_LIMIT_RATE environment variable: must be a positive number")
+			}
+		}
+		defaultRateLimit.Burst = getEnvAsInt("RATE_LIMIT_BURST", defaultRateLimit.Burst)
+		if defaultRateLimit.Burst < 1 {
+			panic("Invalid RATE_LIMIT_BURST environment variable: must be at least 1")
+		}
+
+		rateLimitRoutes, err := parseRateLimitRoutes(os.Getenv("RATE_LIMIT_ROUTES"))
+		if err != nil {
+			panic("Invalid RATE_LIMIT_ROUTES environment variable: " + err.Error())
+		}
+
+		// Load metrics config
+		metricsExporter := os.Getenv("METRICS_EXPORTER")
+		if metricsExporter == "" {
+			metricsExporter = "prometheus"
+		}
+		if metricsExporter != "prometheus" && metricsExporter != "otlp" {
+			panic("Invalid METRICS_EXPORTER environment variable: must be prometheus or otlp")
+		}
+
+		metricsOTLPEndpoint := os.Getenv("METRICS_OTLP_ENDPOINT")
+		if metricsOTLPEndpoint == "" {
+			metricsOTLPEndpoint = "otel-collector:4317"
+		}
+
 		cfg = &Config{
 			Server: ServerConfig{
-				Port:         port,
-				ServiceName:  serviceName,
-				LogLevel:     logLevel,
-				Tracing:      tracing,
-				ReadTimeout:  readTimeout,
-				WriteTimeout: writeTimeout,
-				IdleTimeout:  idleTimeout,
+				Port:           port,
+				ServiceName:    serviceName,
+				LogLevel:       logLevel,
+				Tracing:        tracing,
+				ReadTimeout:    readTimeout,
+				WriteTimeout:   writeTimeout,
+				IdleTimeout:    idleTimeout,
+				RequestTimeout: requestTimeout,
+				ShutdownDelay:  shutdownDelay,
+				MaxPageLimit:   maxPageLimit,
+				TrustedProxies: trustedProxies,
+			},
+			Tracing: TracingConfig{
+				Endpoint:    tracingEndpoint,
+				Protocol:    tracingProtocol,
+				Headers:     tracingHeaders,
+				Insecure:    tracingInsecure,
+				CAFile:      os.Getenv("TRACING_OTLP_CA_FILE"),
+				SampleRatio: tracingSampleRatio,
 			},
 			JWT: JWTConfig{
-				Secret: jwtSecret,
-				Expiry: jwtExpiry,
+				Secret:        jwtSecret,
+				Expiry:        jwtExpiry,
+				RefreshExpiry: jwtRefreshExpiry,
 			},
 			Database: DatabaseConfig{
 				URL:                 databaseURL,
@@ -199,6 +422,32 @@ func LoadConfig() *Config {
 				Password: redisPassword,
 				DB:       redisDB,
 			},
+			Cache: CacheConfig{
+				Driver: cacheDriver,
+				TTL:    cacheTTL,
+			},
+			Mail: MailConfig{
+				Driver:       mailDriver,
+				From:         mailFrom,
+				SMTPHost:     smtpHost,
+				SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+				SMTPUsername: os.Getenv("SMTP_USERNAME"),
+				SMTPPassword: os.Getenv("SMTP_PASSWORD"),
+				FilePath:     mailFilePath,
+			},
+			Search: SearchConfig{
+				Driver:    searchDriver,
+				IndexPath: os.Getenv("SEARCH_INDEX_PATH"),
+			},
+			RateLimit: RateLimitConfig{
+				Enabled: rateLimitEnabled,
+				Default: defaultRateLimit,
+				Routes:  rateLimitRoutes,
+			},
+			Metrics: MetricsConfig{
+				Exporter:     metricsExporter,
+				OTLPEndpoint: metricsOTLPEndpoint,
+			},
 		}
 	})
 
@@ -218,3 +467,80 @@ func getEnvAsInt(name string, defaultValue int) int {
 	}
 	return value
 }
+
+// parseRateLimitRoutes parses per-route limits in the form "books.list=5:10,auth.login=0.5:5",
+// where each value is the refill rate per second and the burst size.
+func parseRateLimitRoutes(value string) (map[string]RateLimit, error) {
+	routes := make(map[string]RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(entry, "=")
+		rateStr, burstStr, hasBurst := strings.Cut(limit, ":")
+		if !ok || !hasBurst || route == "" {
+			return nil, fmt.Errorf("%q must be route=rate:burst", entry)
+		}
+
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("%q: rate must be a positive number", entry)
+		}
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("%q: burst must be at least 1", entry)
+		}
+
+		routes[strings.TrimSpace(route)] = RateLimit{Rate: rate, Burst: burst}
+	}
+	return routes, nil
+}
+
+// parseTrustedProxies parses comma-separated CIDRs or single addresses, such as
+// "10.0.0.0/8,192.168.1.10".
+func parseTrustedProxies(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%q must be an IP address or CIDR", entry)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q must be an IP address or CIDR", entry)
+		}
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
+}
+
+// parseKeyValues parses comma-separated key=value pairs, such as "api-key=secret,tenant=books".
+func parseKeyValues(value string) (map[string]string, error) {
+	pairs := make(map[string]string)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		key, val, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("%q must be key=value", entry)
+		}
+		pairs[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return pairs, nil
+}
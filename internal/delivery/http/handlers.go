@@ -2,10 +2,13 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gorilla/mux"
 	"github.com/masatrio/bookstore-api/internal/delivery/http/middleware"
 	"github.com/masatrio/bookstore-api/internal/domain/delivery"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase"
@@ -14,122 +17,898 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// maxSearchQueryLength bounds the q parameter on book listings.
+const maxSearchQueryLength = 200
+
 type Handler struct {
-	userUseCase  usecase.UserUseCase
-	bookUseCase  usecase.BookUseCase
-	orderUseCase usecase.OrderUseCase
+	userUseCase     usecase.UserUseCase
+	bookUseCase     usecase.BookUseCase
+	orderUseCase    usecase.OrderUseCase
+	cartUseCase     usecase.CartUseCase
+	categoryUseCase usecase.CategoryUseCase
+	maxLimit        int
+}
+
+// NewHandler creates a new HTTP Handler. Listing endpoints cap the requested limit at maxLimit.
+func NewHandler(
+	userUseCase usecase.UserUseCase,
+	bookUseCase usecase.BookUseCase,
+	orderUseCase usecase.OrderUseCase,
+	cartUseCase usecase.CartUseCase,
+	categoryUseCase usecase.CategoryUseCase,
+	maxLimit int,
+) delivery.HTTPHandler {
+	return &Handler{
+		userUseCase:     userUseCase,
+		bookUseCase:     bookUseCase,
+		orderUseCase:    orderUseCase,
+		cartUseCase:     cartUseCase,
+		categoryUseCase: categoryUseCase,
+		maxLimit:        maxLimit,
+	}
+}
+
+// RegisterHandler handles user registration.
+func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RegisterHandler")
+	defer span.End()
+
+	var input usecase.RegisterInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Name == "" || input.Email == "" || input.Password == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Name, email, and password are required"))
+		return
+	}
+
+	output, err := h.userUseCase.Register(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "User registered successfully")
+	jsonResponse(w, http.StatusCreated, output)
+}
+
+// LoginHandler handles user login.
+func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "LoginHandler")
+	defer span.End()
+
+	var input usecase.LoginInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Email == "" || input.Password == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Email and password are required"))
+		return
+	}
+	input.IP = middleware.ClientIP(r)
+
+	output, err := h.userUseCase.Login(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, "Unauthorized")
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Login successful")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// RefreshHandler handles exchanging a refresh token for a new token pair.
+func (h *Handler) RefreshHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RefreshHandler")
+	defer span.End()
+
+	var input usecase.RefreshInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.RefreshToken == "" {
+		span.SetStatus(codes.Error, "Refresh token is required")
+		errorResponse(w, utils.NewCustomUserError("Refresh token is required"))
+		return
+	}
+
+	output, err := h.userUseCase.Refresh(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Token refreshed successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// LogoutHandler handles revoking the caller's access token and, optionally, their refresh token.
+func (h *Handler) LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "LogoutHandler")
+	defer span.End()
+
+	// The refresh token is optional, so an empty body is fine.
+	var input usecase.LogoutInput
+	if r.ContentLength != 0 {
+		if err := parseAndValidate(r, &input); err != nil {
+			span.SetStatus(codes.Error, "Invalid request data")
+			errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+			return
+		}
+	}
+
+	claims, ok := middleware.GetClaimsFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "Token claims not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	accessToken := usecase.AccessToken{
+		UserID:    claims.UserID,
+		ID:        claims.Id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}
+	if err := h.userUseCase.Logout(ctx, input, accessToken); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Logout successful")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeUserSessionsHandler handles an admin revoking every token of a user.
+func (h *Handler) RevokeUserSessionsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RevokeUserSessionsHandler")
+	defer span.End()
+
+	userID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid user ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid User ID"))
+		return
+	}
+
+	if err := h.userUseCase.RevokeSessions(ctx, userID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Sessions revoked successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ForgotPasswordHandler handles requesting a password reset email.
+func (h *Handler) ForgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ForgotPasswordHandler")
+	defer span.End()
+
+	var input usecase.ForgotPasswordInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Email == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Email is required"))
+		return
+	}
+
+	if err := h.userUseCase.ForgotPassword(ctx, input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Password reset requested")
+	jsonResponse(w, http.StatusAccepted, map[string]string{
+		"message": "If the email is registered, a password reset token has been sent",
+	})
+}
+
+// ResetPasswordHandler handles setting a new password with a reset token.
+func (h *Handler) ResetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ResetPasswordHandler")
+	defer span.End()
+
+	var input usecase.ResetPasswordInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Token == "" || input.Password == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Token and password are required"))
+		return
+	}
+
+	if err := h.userUseCase.ResetPassword(ctx, input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Password reset successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// VerifyEmailHandler handles confirming an email address with a verification token.
+func (h *Handler) VerifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "VerifyEmailHandler")
+	defer span.End()
+
+	var input usecase.VerifyEmailInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Token == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Token is required"))
+		return
+	}
+
+	if err := h.userUseCase.VerifyEmail(ctx, input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Email verified successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListBooksHandler handles listing books with optional filtering.
+func (h *Handler) ListBooksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListBooksHandler")
+	defer span.End()
+
+	input, err := h.parseListBooksInput(r)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.bookUseCase.ListBooks(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Books retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// ListAuthorBooksHandler handles listing the books an author is credited on. It takes the same
+// query parameters as ListBooksHandler.
+func (h *Handler) ListAuthorBooksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListAuthorBooksHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid author ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Author ID"))
+		return
+	}
+
+	input, err := h.parseListBooksInput(r)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.bookUseCase.ListAuthorBooks(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Author books retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// ListPublisherBooksHandler handles listing the books of a publisher. It takes the same query
+// parameters as ListBooksHandler.
+func (h *Handler) ListPublisherBooksHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListPublisherBooksHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid publisher ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Publisher ID"))
+		return
+	}
+
+	input, err := h.parseListBooksInput(r)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.bookUseCase.ListPublisherBooks(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Publisher books retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// parseListBooksInput reads the book listing filters, sorting and pagination from the query.
+func (h *Handler) parseListBooksInput(r *http.Request) (usecase.ListBooksInput, utils.CustomError) {
+	input := usecase.ListBooksInput{
+		Query:     strings.TrimSpace(r.URL.Query().Get("q")),
+		Title:     r.URL.Query().Get("title"),
+		Author:    r.URL.Query().Get("author"),
+		MinPrice:  parseFloatOrDefault(r.URL.Query().Get("min_price"), 0),
+		MaxPrice:  parseFloatOrDefault(r.URL.Query().Get("max_price"), 0),
+		StartDate: parseDateOrDefault(r.URL.Query().Get("start_date")),
+		EndDate:   parseDateOrDefault(r.URL.Query().Get("end_date")),
+		Category:  r.URL.Query().Get("category"),
+		Sort:      r.URL.Query().Get("sort"),
+		Cursor:    r.URL.Query().Get("cursor"),
+		Limit:     h.parseLimit(r),
+		Offset:    parseIntOrDefault(r.URL.Query().Get("offset"), 0),
+	}
+
+	if len(input.Query) > maxSearchQueryLength {
+		return input, utils.NewCustomUserError(fmt.Sprintf("q must be at most %d characters", maxSearchQueryLength))
+	}
+	return input, nil
+}
+
+// GetBookHandler handles retrieving a single book by its ID.
+func (h *Handler) GetBookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetBookHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	output, err := h.bookUseCase.GetBook(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// CreateBookHandler handles creating a new book.
+func (h *Handler) CreateBookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CreateBookHandler")
+	defer span.End()
+
+	var input usecase.Book
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if err := validateBookInput(usecase.UpdateBookInput{
+		Title:  &input.Title,
+		Author: &input.Author,
+		Price:  &input.Price,
+	}, true); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	if input.Stock < 0 {
+		span.SetStatus(codes.Error, "Invalid stock")
+		errorResponse(w, utils.NewCustomUserError("Stock must not be negative"))
+		return
+	}
+
+	output, err := h.bookUseCase.CreateBook(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book created successfully")
+	jsonResponse(w, http.StatusCreated, output)
+}
+
+// UpdateBookHandler handles updating a book. PUT requires every field, PATCH only the ones being changed.
+func (h *Handler) UpdateBookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "UpdateBookHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	var input usecase.UpdateBookInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if err := validateBookInput(input, r.Method == http.MethodPut); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.bookUseCase.UpdateBook(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book updated successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// DeleteBookHandler handles deleting a book.
+func (h *Handler) DeleteBookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "DeleteBookHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	if err := h.bookUseCase.DeleteBook(ctx, id); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book deleted successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RestockBookHandler handles adding stock to a book.
+func (h *Handler) RestockBookHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RestockBookHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	var input usecase.RestockInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Quantity <= 0 {
+		span.SetStatus(codes.Error, "Invalid quantity")
+		errorResponse(w, utils.NewCustomUserError("Quantity must be greater than zero"))
+		return
+	}
+
+	output, err := h.bookUseCase.RestockBook(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book restocked successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// ListStockHandler handles listing stock levels, lowest first.
+func (h *Handler) ListStockHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListStockHandler")
+	defer span.End()
+
+	input := usecase.ListStockInput{
+		Limit:  h.parseLimit(r),
+		Offset: parseIntOrDefault(r.URL.Query().Get("offset"), 0),
+	}
+	if value := r.URL.Query().Get("max_stock"); value != "" {
+		maxStock, err := strconv.Atoi(value)
+		if err != nil {
+			span.SetStatus(codes.Error, "Invalid max_stock")
+			errorResponse(w, utils.NewCustomUserError("max_stock must be an integer"))
+			return
+		}
+		input.MaxStock = &maxStock
+	}
+
+	output, err := h.bookUseCase.ListStock(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Stock levels retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// CreateOrderHandler handles creating a new order.
+func (h *Handler) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CreateOrderHandler")
+	defer span.End()
+
+	var input usecase.CreateOrderInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	// Validate the input
+	if err := validateCreateOrderInput(input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.orderUseCase.CreateOrder(ctx, input, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Order created successfully")
+	jsonResponse(w, http.StatusCreated, output)
+}
+
+// GetOrdersHandler handles retrieving orders with pagination.
+func (h *Handler) GetOrdersHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetOrdersHandler")
+	defer span.End()
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	input := usecase.GetOrdersInput{
+		Sort:   r.URL.Query().Get("sort"),
+		Cursor: r.URL.Query().Get("cursor"),
+		Limit:  h.parseLimit(r),
+		Offset: parseIntOrDefault(r.URL.Query().Get("offset"), 0),
+	}
+
+	output, err := h.orderUseCase.GetOrders(ctx, userID, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Orders retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// GetOrderHandler handles retrieving a single order of the authenticated user.
+func (h *Handler) GetOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetOrderHandler")
+	defer span.End()
+
+	orderID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid order ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Order ID"))
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	output, err := h.orderUseCase.GetOrder(ctx, orderID, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Order retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// UpdateOrderStatusHandler handles moving an order to a new status.
+func (h *Handler) UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "UpdateOrderStatusHandler")
+	defer span.End()
+
+	orderID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid order ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Order ID"))
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	var input usecase.UpdateOrderStatusInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if input.Status == "" {
+		span.SetStatus(codes.Error, "Missing required fields")
+		errorResponse(w, utils.NewCustomUserError("Status is required"))
+		return
+	}
+
+	output, err := h.orderUseCase.UpdateOrderStatus(ctx, orderID, input, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Order status updated successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// CancelOrderHandler handles a customer cancelling their own order.
+func (h *Handler) CancelOrderHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CancelOrderHandler")
+	defer span.End()
+
+	orderID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid order ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Order ID"))
+		return
+	}
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	output, err := h.orderUseCase.CancelOrder(ctx, orderID, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Order cancelled successfully")
+	jsonResponse(w, http.StatusOK, output)
 }
 
-// NewHandler creates a new HTTP Handler.
-func NewHandler(
-	userUseCase usecase.UserUseCase,
-	bookUseCase usecase.BookUseCase,
-	orderUseCase usecase.OrderUseCase,
-) delivery.HTTPHandler {
-	return &Handler{
-		userUseCase:  userUseCase,
-		bookUseCase:  bookUseCase,
-		orderUseCase: orderUseCase,
+// GetCartHandler handles retrieving the authenticated user's cart.
+func (h *Handler) GetCartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetCartHandler")
+	defer span.End()
+
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	output, err := h.cartUseCase.GetCart(ctx, userID)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
 	}
+
+	span.SetStatus(codes.Ok, "Cart retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
 }
 
-// RegisterHandler handles user registration.
-func (h *Handler) RegisterHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RegisterHandler")
+// AddCartItemHandler handles adding a book to the authenticated user's cart.
+func (h *Handler) AddCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "AddCartItemHandler")
 	defer span.End()
 
-	var input usecase.RegisterInput
+	var input usecase.AddCartItemInput
 	if err := parseAndValidate(r, &input); err != nil {
 		span.SetStatus(codes.Error, "Invalid request data")
 		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
 		return
 	}
 
-	if input.Name == "" || input.Email == "" || input.Password == "" {
-		span.SetStatus(codes.Error, "Missing required fields")
-		errorResponse(w, utils.NewCustomUserError("Name, email, and password are required"))
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
 		return
 	}
 
-	output, err := h.userUseCase.Register(ctx, input)
+	if input.BookID <= 0 {
+		span.SetStatus(codes.Error, "Invalid Book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+	if input.Quantity <= 0 {
+		span.SetStatus(codes.Error, "Invalid quantity")
+		errorResponse(w, utils.NewCustomUserError("Quantity must be greater than zero"))
+		return
+	}
+
+	output, err := h.cartUseCase.AddItem(ctx, userID, input)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	span.SetStatus(codes.Ok, "User registered successfully")
-	jsonResponse(w, http.StatusCreated, output)
+	span.SetStatus(codes.Ok, "Cart item added successfully")
+	jsonResponse(w, http.StatusOK, output)
 }
 
-// LoginHandler handles user login.
-func (h *Handler) LoginHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "LoginHandler")
+// UpdateCartItemHandler handles changing the quantity of a book in the authenticated user's cart.
+func (h *Handler) UpdateCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "UpdateCartItemHandler")
 	defer span.End()
 
-	var input usecase.LoginInput
+	bookID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	var input usecase.UpdateCartItemInput
 	if err := parseAndValidate(r, &input); err != nil {
 		span.SetStatus(codes.Error, "Invalid request data")
 		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
 		return
 	}
 
-	if input.Email == "" || input.Password == "" {
-		span.SetStatus(codes.Error, "Missing required fields")
-		errorResponse(w, utils.NewCustomUserError("Email and password are required"))
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
 		return
 	}
 
-	output, err := h.userUseCase.Login(ctx, input)
+	if input.Quantity <= 0 {
+		span.SetStatus(codes.Error, "Invalid quantity")
+		errorResponse(w, utils.NewCustomUserError("Quantity must be greater than zero"))
+		return
+	}
+
+	output, err := h.cartUseCase.UpdateItem(ctx, userID, bookID, input)
 	if err != nil {
-		span.SetStatus(codes.Error, "Unauthorized")
+		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	span.SetStatus(codes.Ok, "Login successful")
+	span.SetStatus(codes.Ok, "Cart item updated successfully")
 	jsonResponse(w, http.StatusOK, output)
 }
 
-// ListBooksHandler handles listing books with optional filtering.
-func (h *Handler) ListBooksHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListBooksHandler")
+// RemoveCartItemHandler handles removing a book from the authenticated user's cart.
+func (h *Handler) RemoveCartItemHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RemoveCartItemHandler")
 	defer span.End()
 
-	input := usecase.ListBooksInput{
-		Title:     r.URL.Query().Get("title"),
-		Author:    r.URL.Query().Get("author"),
-		MinPrice:  parseFloatOrDefault(r.URL.Query().Get("min_price"), 0),
-		MaxPrice:  parseFloatOrDefault(r.URL.Query().Get("max_price"), 0),
-		StartDate: parseDateOrDefault(r.URL.Query().Get("start_date")),
-		EndDate:   parseDateOrDefault(r.URL.Query().Get("end_date")),
-		Limit:     parseIntOrDefault(r.URL.Query().Get("limit"), 10),
-		Offset:    parseIntOrDefault(r.URL.Query().Get("offset"), 0),
+	bookID, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
 	}
 
-	output, err := h.bookUseCase.ListBooks(ctx, input)
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	output, err := h.cartUseCase.RemoveItem(ctx, userID, bookID)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	span.SetStatus(codes.Ok, "Books retrieved successfully")
+	span.SetStatus(codes.Ok, "Cart item removed successfully")
 	jsonResponse(w, http.StatusOK, output)
 }
 
-// CreateOrderHandler handles creating a new order.
-func (h *Handler) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CreateOrderHandler")
+// ClearCartHandler handles emptying the authenticated user's cart.
+func (h *Handler) ClearCartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ClearCartHandler")
 	defer span.End()
 
-	var input usecase.CreateOrderInput
-	if err := parseAndValidate(r, &input); err != nil {
-		span.SetStatus(codes.Error, "Invalid request data")
-		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+	userID, ok := middleware.GetUserIDFromContext(ctx)
+	if !ok {
+		span.SetStatus(codes.Error, "User ID not found in context")
+		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		return
+	}
+
+	if err := h.cartUseCase.ClearCart(ctx, userID); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
 		return
 	}
 
+	span.SetStatus(codes.Ok, "Cart cleared successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CheckoutCartHandler handles turning the authenticated user's cart into an order.
+func (h *Handler) CheckoutCartHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CheckoutCartHandler")
+	defer span.End()
+
 	userID, ok := middleware.GetUserIDFromContext(ctx)
 	if !ok {
 		span.SetStatus(codes.Error, "User ID not found in context")
@@ -137,48 +916,209 @@ func (h *Handler) CreateOrderHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate the input
-	if err := validateCreateOrderInput(input); err != nil {
+	output, err := h.cartUseCase.Checkout(ctx, userID)
+	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	output, err := h.orderUseCase.CreateOrder(ctx, input, userID)
+	span.SetStatus(codes.Ok, "Cart checked out successfully")
+	jsonResponse(w, http.StatusCreated, output)
+}
+
+// ListCategoriesHandler handles listing every category as a tree.
+func (h *Handler) ListCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "ListCategoriesHandler")
+	defer span.End()
+
+	output, err := h.categoryUseCase.ListCategories(ctx)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	span.SetStatus(codes.Ok, "Order created successfully")
+	span.SetStatus(codes.Ok, "Categories retrieved successfully")
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"categories": output})
+}
+
+// GetCategoryHandler handles retrieving a category with its subcategories.
+func (h *Handler) GetCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetCategoryHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid category ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Category ID"))
+		return
+	}
+
+	output, err := h.categoryUseCase.GetCategory(ctx, id)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Category retrieved successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// CreateCategoryHandler handles creating a category.
+func (h *Handler) CreateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "CreateCategoryHandler")
+	defer span.End()
+
+	var input usecase.CategoryInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	if err := validateCategoryInput(input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	output, err := h.categoryUseCase.CreateCategory(ctx, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Category created successfully")
 	jsonResponse(w, http.StatusCreated, output)
 }
 
-// GetOrdersHandler handles retrieving orders with pagination.
-func (h *Handler) GetOrdersHandler(w http.ResponseWriter, r *http.Request) {
-	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "GetOrdersHandler")
+// UpdateCategoryHandler handles replacing a category's name, slug and parent.
+func (h *Handler) UpdateCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "UpdateCategoryHandler")
 	defer span.End()
 
-	userID, ok := middleware.GetUserIDFromContext(ctx)
+	id, ok := parseIDParam(r)
 	if !ok {
-		span.SetStatus(codes.Error, "User ID not found in context")
-		errorResponse(w, utils.NewCustomSystemError("System Error"))
+		span.SetStatus(codes.Error, "Invalid category ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Category ID"))
+		return
+	}
+
+	var input usecase.CategoryInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
 		return
 	}
 
-	limit := parseIntOrDefault(r.URL.Query().Get("limit"), 10)
-	offset := parseIntOrDefault(r.URL.Query().Get("offset"), 0)
+	if err := validateCategoryInput(input); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
 
-	orders, err := h.orderUseCase.GetOrders(ctx, userID, limit, offset)
+	output, err := h.categoryUseCase.UpdateCategory(ctx, id, input)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
 		errorResponse(w, err)
 		return
 	}
 
-	span.SetStatus(codes.Ok, "Orders retrieved successfully")
-	jsonResponse(w, http.StatusOK, map[string]interface{}{"orders": orders})
+	span.SetStatus(codes.Ok, "Category updated successfully")
+	jsonResponse(w, http.StatusOK, output)
+}
+
+// DeleteCategoryHandler handles deleting a category.
+func (h *Handler) DeleteCategoryHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "DeleteCategoryHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid category ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Category ID"))
+		return
+	}
+
+	if err := h.categoryUseCase.DeleteCategory(ctx, id); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Category deleted successfully")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetBookCategoriesHandler handles replacing the categories a book is assigned to.
+func (h *Handler) SetBookCategoriesHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "SetBookCategoriesHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	var input usecase.SetBookCategoriesInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	for _, categoryID := range input.CategoryIDs {
+		if categoryID <= 0 {
+			span.SetStatus(codes.Error, "Invalid category ID")
+			errorResponse(w, utils.NewCustomUserError("Invalid Category ID"))
+			return
+		}
+	}
+
+	output, err := h.categoryUseCase.SetBookCategories(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book categories updated successfully")
+	jsonResponse(w, http.StatusOK, map[string]interface{}{"categories": output})
+}
+
+// SetBookAuthorsHandler handles replacing the authors, translators and editors credited on a book.
+func (h *Handler) SetBookAuthorsHandler(w http.ResponseWriter, r *http.Request) {
+	ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "SetBookAuthorsHandler")
+	defer span.End()
+
+	id, ok := parseIDParam(r)
+	if !ok {
+		span.SetStatus(codes.Error, "Invalid book ID")
+		errorResponse(w, utils.NewCustomUserError("Invalid Book ID"))
+		return
+	}
+
+	var input usecase.SetBookAuthorsInput
+	if err := parseAndValidate(r, &input); err != nil {
+		span.SetStatus(codes.Error, "Invalid request data")
+		errorResponse(w, utils.NewCustomUserError("Invalid request data"))
+		return
+	}
+
+	output, err := h.bookUseCase.SetBookAuthors(ctx, id, input)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		errorResponse(w, err)
+		return
+	}
+
+	span.SetStatus(codes.Ok, "Book authors updated successfully")
+	jsonResponse(w, http.StatusOK, output)
 }
 
 // HealthCheckHandler handles health check requests.
@@ -214,6 +1154,27 @@ func parseIntOrDefault(value string, defaultValue int) int {
 	return parsed
 }
 
+// defaultLimit is the page size of listing endpoints when the request does not give a limit.
+const defaultLimit = 10
+
+// parseLimit reads the limit query parameter, capped at the handler's maximum when one is set.
+func (h *Handler) parseLimit(r *http.Request) int {
+	limit := parseIntOrDefault(r.URL.Query().Get("limit"), defaultLimit)
+	if h.maxLimit > 0 && limit > h.maxLimit {
+		return h.maxLimit
+	}
+	return limit
+}
+
+// parseIDParam parses the "id" path variable as a positive int64.
+func parseIDParam(r *http.Request) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
 // parseDateOrDefault parses date or returns zero time.
 func parseDateOrDefault(value string) time.Time {
 	if value == "" {
@@ -240,6 +1201,26 @@ func jsonResponse(w http.ResponseWriter, status int, data interface{}) {
 
 // errorResponse writes an error response based on custom errors.
 func errorResponse(w http.ResponseWriter, err utils.CustomError) {
+	if err.IsNotFoundError() {
+		jsonResponse(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+		return
+	}
+	if err.IsConflictError() {
+		jsonResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+		return
+	}
+	if err.IsAuthError() {
+		jsonResponse(w, http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		return
+	}
+	if err.IsTooManyRequestsError() {
+		jsonResponse(w, http.StatusTooManyRequests, map[string]string{"error": err.Error()})
+		return
+	}
+	if err.IsTimeoutError() {
+		jsonResponse(w, http.StatusGatewayTimeout, map[string]string{"error": err.Error()})
+		return
+	}
 	if err.IsUserError() {
 		jsonResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 		return
@@ -269,3 +1250,38 @@ func validateCreateOrderInput(input usecase.CreateOrderInput) utils.CustomError
 
 	return nil
 }
+
+// validateCategoryInput validates the input for creating or replacing a category.
+func validateCategoryInput(input usecase.CategoryInput) utils.CustomError {
+	if strings.TrimSpace(input.Name) == "" {
+		return utils.NewCustomUserError("Name is required")
+	}
+	if len(input.Name) > 255 || len(input.Slug) > 255 {
+		return utils.NewCustomUserError("Name and slug must be at most 255 characters")
+	}
+	if input.ParentID != nil && *input.ParentID <= 0 {
+		return utils.NewCustomUserError("Invalid Parent Category ID")
+	}
+
+	return nil
+}
+
+// validateBookInput validates the input for creating or updating a book.
+// When requireAll is set, every field must be present.
+func validateBookInput(input usecase.UpdateBookInput, requireAll bool) utils.CustomError {
+	if requireAll && (input.Title == nil || input.Author == nil || input.Price == nil) {
+		return utils.NewCustomUserError("Title, author, and price are required")
+	}
+
+	if input.Title != nil && *input.Title == "" {
+		return utils.NewCustomUserError("Title must not be empty")
+	}
+	if input.Author != nil && *input.Author == "" {
+		return utils.NewCustomUserError("Author must not be empty")
+	}
+	if input.Price != nil && *input.Price <= 0 {
+		return utils.NewCustomUserError("Price must be greater than zero")
+	}
+
+	return nil
+}
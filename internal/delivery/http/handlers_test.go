@@ -2,12 +2,18 @@ package http
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/masatrio/bookstore-api/internal/delivery/http/middleware"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase/mocks"
 	"github.com/masatrio/bookstore-api/utils"
@@ -79,7 +85,7 @@ func TestLoginHandler(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserUseCase := mocks.NewMockUserUseCase(ctrl)
-	handler := NewHandler(mockUserUseCase, nil, nil)
+	handler := NewHandler(mockUserUseCase, nil, nil, nil, nil, 0)
 
 	tests := []struct {
 		name           string
@@ -92,6 +98,7 @@ func TestLoginHandler(t *testing.T) {
 			input: usecase.LoginInput{
 				Email:    "satrio@example.com",
 				Password: "securepassword",
+				IP:       "192.0.2.1",
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -100,6 +107,7 @@ func TestLoginHandler(t *testing.T) {
 			input: usecase.LoginInput{
 				Email:    "satrio@example.com",
 				Password: "securepassword",
+				IP:       "192.0.2.1",
 			},
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "Email and password are required",
@@ -132,6 +140,197 @@ func TestLoginHandler(t *testing.T) {
 	}
 }
 
+func TestRefreshHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserUseCase := mocks.NewMockUserUseCase(ctrl)
+	handler := &Handler{userUseCase: mockUserUseCase}
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedStatus int
+		expectCall     bool
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			input:          `{"refresh_token":"valid"}`,
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Token",
+			input:          `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Reused Token",
+			input:          `{"refresh_token":"rotated"}`,
+			expectedStatus: http.StatusUnauthorized,
+			expectCall:     true,
+			mockError:      utils.NewCustomAuthError("invalid refresh token"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", bytes.NewBufferString(tt.input))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				var output *usecase.RefreshOutput
+				if tt.mockError == nil {
+					output = &usecase.RefreshOutput{Token: "access", RefreshToken: "refresh"}
+				}
+				mockUserUseCase.EXPECT().Refresh(gomock.Any(), gomock.Any()).Return(output, tt.mockError)
+			}
+
+			handler.RefreshHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestLogoutHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserUseCase := mocks.NewMockUserUseCase(ctrl)
+	handler := &Handler{userUseCase: mockUserUseCase}
+
+	claims := &utils.Claims{UserID: 7, Role: usecase.RoleCustomer}
+	claims.Id = "token-id"
+	claims.ExpiresAt = 1700000000
+
+	tests := []struct {
+		name          string
+		body          string
+		expectedInput usecase.LogoutInput
+	}{
+		{
+			name: "Without Refresh Token",
+		},
+		{
+			name:          "With Refresh Token",
+			body:          `{"refresh_token":"refresh"}`,
+			expectedInput: usecase.LogoutInput{RefreshToken: "refresh"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/logout", bytes.NewBufferString(tt.body))
+			req = req.WithContext(middleware.ContextWithClaims(req.Context(), claims))
+			w := httptest.NewRecorder()
+
+			mockUserUseCase.EXPECT().Logout(gomock.Any(), tt.expectedInput, usecase.AccessToken{
+				UserID:    7,
+				ID:        "token-id",
+				ExpiresAt: time.Unix(1700000000, 0),
+			}).Return(nil)
+
+			handler.LogoutHandler(w, req)
+
+			assert.Equal(t, http.StatusNoContent, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestForgotPasswordHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserUseCase := mocks.NewMockUserUseCase(ctrl)
+	handler := &Handler{userUseCase: mockUserUseCase}
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name:           "Accepted",
+			input:          `{"email":"satrio@test.test"}`,
+			expectedStatus: http.StatusAccepted,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Email",
+			input:          `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/forgot-password", bytes.NewBufferString(tt.input))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				mockUserUseCase.EXPECT().ForgotPassword(gomock.Any(), usecase.ForgotPasswordInput{Email: "satrio@test.test"}).Return(nil)
+			}
+
+			handler.ForgotPasswordHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestResetPasswordHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserUseCase := mocks.NewMockUserUseCase(ctrl)
+	handler := &Handler{userUseCase: mockUserUseCase}
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedStatus int
+		expectCall     bool
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			input:          `{"token":"valid","password":"new-password"}`,
+			expectedStatus: http.StatusNoContent,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Password",
+			input:          `{"token":"valid"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Used Token",
+			input:          `{"token":"used","password":"new-password"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectCall:     true,
+			mockError:      utils.NewCustomUserError("invalid or expired token"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/reset-password", bytes.NewBufferString(tt.input))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				mockUserUseCase.EXPECT().ResetPassword(gomock.Any(), gomock.Any()).Return(tt.mockError)
+			}
+
+			handler.ResetPasswordHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
 func TestListBooksHandler(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -158,6 +357,12 @@ func TestListBooksHandler(t *testing.T) {
 			expectedStatus: http.StatusInternalServerError,
 			mockError:      utils.NewCustomSystemError("System Error"),
 		},
+		{
+			name:           "Database timeout",
+			queryParams:    "?title=Go",
+			expectedStatus: http.StatusGatewayTimeout,
+			mockError:      utils.NewCustomTimeoutError("Database Timeout"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -181,6 +386,764 @@ func TestListBooksHandler(t *testing.T) {
 	}
 }
 
+func TestListBooksHandler_Search(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	mockBookUseCase.EXPECT().ListBooks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input usecase.ListBooksInput) (*usecase.ListBooksOutput, utils.CustomError) {
+			assert.Equal(t, "tolkien hobbit", input.Query)
+			return &usecase.ListBooksOutput{}, nil
+		})
+
+	w := httptest.NewRecorder()
+	handler.ListBooksHandler(w, httptest.NewRequest(http.MethodGet, "/books?q=+tolkien+hobbit+", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	handler.ListBooksHandler(w, httptest.NewRequest(http.MethodGet, "/books?q="+strings.Repeat("a", maxSearchQueryLength+1), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetBookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+		mockResponse   *usecase.Book
+		mockError      utils.CustomError
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			id:             "1",
+			expectedStatus: http.StatusOK,
+			mockResponse:   &usecase.Book{ID: 1, Title: "The Hobbit"},
+			expectCall:     true,
+		},
+		{
+			name:           "Not Found",
+			id:             "99",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Book ID Not Found"),
+			expectCall:     true,
+		},
+		{
+			name:           "Invalid ID",
+			id:             "0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/books/"+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				mockBookUseCase.EXPECT().GetBook(gomock.Any(), gomock.Any()).Return(tt.mockResponse, tt.mockError)
+			}
+
+			handler.GetBookHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestCreateBookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	tests := []struct {
+		name           string
+		input          usecase.Book
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			input:          usecase.Book{Title: "The Hobbit", Author: "J.R.R. Tolkien", Price: 150000},
+			expectedStatus: http.StatusCreated,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Title",
+			input:          usecase.Book{Author: "J.R.R. Tolkien", Price: 150000},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Price",
+			input:          usecase.Book{Title: "The Hobbit", Author: "J.R.R. Tolkien"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.input)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/books", bytes.NewReader(body))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				mockBookUseCase.EXPECT().CreateBook(gomock.Any(), gomock.Any()).Return(&usecase.Book{ID: 1}, nil)
+			}
+
+			handler.CreateBookHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestUpdateBookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		mockError      utils.CustomError
+		expectCall     bool
+	}{
+		{
+			name:           "Patch Price Only",
+			method:         http.MethodPatch,
+			body:           `{"price": 99000}`,
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Put Missing Fields",
+			method:         http.MethodPut,
+			body:           `{"price": 99000}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Not Found",
+			method:         http.MethodPut,
+			body:           `{"title": "1984", "author": "George Orwell", "price": 120000}`,
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Book ID Not Found"),
+			expectCall:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/books/1", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				if tt.mockError != nil {
+					mockBookUseCase.EXPECT().UpdateBook(gomock.Any(), int64(1), gomock.Any()).Return(nil, tt.mockError)
+				} else {
+					mockBookUseCase.EXPECT().UpdateBook(gomock.Any(), int64(1), gomock.Any()).Return(&usecase.Book{ID: 1}, nil)
+				}
+			}
+
+			handler.UpdateBookHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestDeleteBookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "Not Found",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Book ID Not Found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/books/1", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			mockBookUseCase.EXPECT().DeleteBook(gomock.Any(), int64(1)).Return(tt.mockError)
+
+			handler.DeleteBookHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestRestockBookHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := &Handler{bookUseCase: mockBookUseCase}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			body:           `{"quantity": 10}`,
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Invalid Quantity",
+			body:           `{"quantity": 0}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/books/1/restock", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				mockBookUseCase.EXPECT().
+					RestockBook(gomock.Any(), int64(1), usecase.RestockInput{Quantity: 10}).
+					Return(&usecase.Book{ID: 1, Stock: 10}, nil)
+			}
+
+			handler.RestockBookHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestListAuthorBooksHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookUseCase := mocks.NewMockBookUseCase(ctrl)
+	handler := NewHandler(nil, mockBookUseCase, nil, nil, nil, 50)
+
+	tests := []struct {
+		name           string
+		id             string
+		query          string
+		expectedStatus int
+		mockError      utils.CustomError
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			id:             "7",
+			query:          "?sort=-created_at&limit=100",
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Not Found",
+			id:             "99",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Author Not Found"),
+			expectCall:     true,
+		},
+		{
+			name:           "Invalid ID",
+			id:             "0",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/authors/"+tt.id+"/books"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				var output *usecase.AuthorBooksOutput
+				if tt.mockError == nil {
+					output = &usecase.AuthorBooksOutput{
+						Author: usecase.Author{ID: 7, Name: "J.R.R. Tolkien"},
+						ListBooksOutput: usecase.ListBooksOutput{
+							Books: []usecase.Book{{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien"}},
+						},
+					}
+				}
+				mockBookUseCase.EXPECT().
+					ListAuthorBooks(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, authorID int64, input usecase.ListBooksInput) (*usecase.AuthorBooksOutput, utils.CustomError) {
+						assert.Equal(t, tt.id, strconv.FormatInt(authorID, 10))
+						if tt.query != "" {
+							// The listing limit is capped like the regular book listing.
+							assert.Equal(t, "-created_at", input.Sort)
+							assert.Equal(t, 50, input.Limit)
+						}
+						return output, tt.mockError
+					})
+			}
+
+			handler.ListAuthorBooksHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				var body map[string]interface{}
+				assert.NoError(t, json.NewDecoder(w.Body).Decode(&body))
+				assert.Contains(t, body, "author")
+				assert.Contains(t, body, "books")
+			}
+		})
+	}
+}
+
+func TestCreateCategoryHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryUseCase := mocks.NewMockCategoryUseCase(ctrl)
+	handler := &Handler{categoryUseCase: mockCategoryUseCase}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockError      utils.CustomError
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			body:           `{"name": "Epic Fantasy", "parent_id": 2}`,
+			expectedStatus: http.StatusCreated,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Name",
+			body:           `{"slug": "epic-fantasy"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Parent",
+			body:           `{"name": "Epic Fantasy", "parent_id": 0}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Slug Taken",
+			body:           `{"name": "Epic Fantasy", "parent_id": 2}`,
+			expectedStatus: http.StatusConflict,
+			mockError:      utils.NewCustomConflictError("Category slug already exists"),
+			expectCall:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/categories", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				parentID := int64(2)
+				var output *usecase.Category
+				if tt.mockError == nil {
+					output = &usecase.Category{ID: 3, ParentID: &parentID, Name: "Epic Fantasy", Slug: "epic-fantasy"}
+				}
+				mockCategoryUseCase.EXPECT().
+					CreateCategory(gomock.Any(), usecase.CategoryInput{ParentID: &parentID, Name: "Epic Fantasy"}).
+					Return(output, tt.mockError)
+			}
+
+			handler.CreateCategoryHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestGetOrdersHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderUseCase := mocks.NewMockOrderUseCase(ctrl)
+	handler := NewHandler(nil, nil, mockOrderUseCase, nil, nil, 50)
+
+	tests := []struct {
+		name          string
+		query         string
+		expectedInput usecase.GetOrdersInput
+	}{
+		{
+			name:          "Defaults",
+			query:         "",
+			expectedInput: usecase.GetOrdersInput{Limit: 10},
+		},
+		{
+			name:          "Offset",
+			query:         "?limit=20&offset=40&sort=-total",
+			expectedInput: usecase.GetOrdersInput{Sort: "-total", Limit: 20, Offset: 40},
+		},
+		{
+			name:          "Cursor",
+			query:         "?cursor=abc",
+			expectedInput: usecase.GetOrdersInput{Cursor: "abc", Limit: 10},
+		},
+		{
+			name:          "Limit Capped",
+			query:         "?limit=1000",
+			expectedInput: usecase.GetOrdersInput{Limit: 50},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/orders"+tt.query, nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			output := &usecase.GetOrdersOutput{
+				Orders:     []usecase.GetOrderOutput{{OrderID: 1, Status: usecase.OrderStatusPending}},
+				NextCursor: "next",
+			}
+			mockOrderUseCase.EXPECT().GetOrders(gomock.Any(), int64(7), tt.expectedInput).Return(output, nil)
+
+			handler.GetOrdersHandler(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+			var response usecase.GetOrdersOutput
+			json.NewDecoder(w.Body).Decode(&response)
+			assert.Equal(t, *output, response)
+		})
+	}
+}
+
+func TestGetOrderHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderUseCase := mocks.NewMockOrderUseCase(ctrl)
+	handler := &Handler{orderUseCase: mockOrderUseCase}
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockResponse   *usecase.GetOrderOutput
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+			mockResponse: &usecase.GetOrderOutput{
+				OrderID: 1,
+				Items:   []usecase.OrderItemDetail{{BookID: 2, Title: "1984", Quantity: 1, UnitPrice: 120000, LineTotal: 120000}},
+				Status:  usecase.OrderStatusPending,
+				Total:   120000,
+			},
+		},
+		{
+			name:           "Other User's Order",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Order Not Found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/1", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			mockOrderUseCase.EXPECT().GetOrder(gomock.Any(), int64(1), int64(7)).Return(tt.mockResponse, tt.mockError)
+
+			handler.GetOrderHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+			if tt.mockResponse != nil {
+				var response usecase.GetOrderOutput
+				json.NewDecoder(w.Body).Decode(&response)
+				assert.Equal(t, *tt.mockResponse, response)
+			}
+		})
+	}
+}
+
+func TestUpdateOrderStatusHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderUseCase := mocks.NewMockOrderUseCase(ctrl)
+	handler := &Handler{orderUseCase: mockOrderUseCase}
+
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		mockError      utils.CustomError
+		expectCall     bool
+	}{
+		{
+			name:           "Success",
+			body:           `{"status": "shipped"}`,
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Missing Status",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid Transition",
+			body:           `{"status": "pending"}`,
+			expectedStatus: http.StatusConflict,
+			mockError:      utils.NewCustomConflictError("Cannot change order status from shipped to pending"),
+			expectCall:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPatch, "/api/v1/orders/1/status", bytes.NewBufferString(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleAdmin))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				if tt.mockError != nil {
+					mockOrderUseCase.EXPECT().UpdateOrderStatus(gomock.Any(), int64(1), gomock.Any(), int64(7)).Return(nil, tt.mockError)
+				} else {
+					mockOrderUseCase.EXPECT().UpdateOrderStatus(gomock.Any(), int64(1), gomock.Any(), int64(7)).
+						Return(&usecase.OrderStatusOutput{OrderID: 1, Status: usecase.OrderStatusShipped}, nil)
+				}
+			}
+
+			handler.UpdateOrderStatusHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestCancelOrderHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOrderUseCase := mocks.NewMockOrderUseCase(ctrl)
+	handler := &Handler{orderUseCase: mockOrderUseCase}
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Other User's Order",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Order Not Found"),
+		},
+		{
+			name:           "Already Shipped",
+			expectedStatus: http.StatusConflict,
+			mockError:      utils.NewCustomConflictError("Cannot change order status from shipped to cancelled"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/orders/1/cancel", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			if tt.mockError != nil {
+				mockOrderUseCase.EXPECT().CancelOrder(gomock.Any(), int64(1), int64(7)).Return(nil, tt.mockError)
+			} else {
+				mockOrderUseCase.EXPECT().CancelOrder(gomock.Any(), int64(1), int64(7)).
+					Return(&usecase.OrderStatusOutput{OrderID: 1, Status: usecase.OrderStatusCancelled}, nil)
+			}
+
+			handler.CancelOrderHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestAddCartItemHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCartUseCase := mocks.NewMockCartUseCase(ctrl)
+	handler := &Handler{cartUseCase: mockCartUseCase}
+
+	tests := []struct {
+		name           string
+		input          string
+		expectedStatus int
+		expectCall     bool
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			input:          `{"book_id":1,"quantity":2}`,
+			expectedStatus: http.StatusOK,
+			expectCall:     true,
+		},
+		{
+			name:           "Invalid Quantity",
+			input:          `{"book_id":1,"quantity":0}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Book Not Found",
+			input:          `{"book_id":99,"quantity":1}`,
+			expectedStatus: http.StatusNotFound,
+			expectCall:     true,
+			mockError:      utils.NewCustomNotFoundError("Book Not Found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/cart/items", bytes.NewBufferString(tt.input))
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			if tt.expectCall {
+				var output *usecase.CartOutput
+				if tt.mockError == nil {
+					output = &usecase.CartOutput{Items: []usecase.CartItemDetail{{BookID: 1, Quantity: 2}}}
+				}
+				mockCartUseCase.EXPECT().AddItem(gomock.Any(), int64(7), gomock.Any()).Return(output, tt.mockError)
+			}
+
+			handler.AddCartItemHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestRemoveCartItemHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCartUseCase := mocks.NewMockCartUseCase(ctrl)
+	handler := &Handler{cartUseCase: mockCartUseCase}
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Not In Cart",
+			expectedStatus: http.StatusNotFound,
+			mockError:      utils.NewCustomNotFoundError("Cart Item Not Found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/cart/items/1", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "1"})
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			var output *usecase.CartOutput
+			if tt.mockError == nil {
+				output = &usecase.CartOutput{}
+			}
+			mockCartUseCase.EXPECT().RemoveItem(gomock.Any(), int64(7), int64(1)).Return(output, tt.mockError)
+
+			handler.RemoveCartItemHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
+func TestCheckoutCartHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCartUseCase := mocks.NewMockCartUseCase(ctrl)
+	handler := &Handler{cartUseCase: mockCartUseCase}
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockError      utils.CustomError
+	}{
+		{
+			name:           "Success",
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "Empty Cart",
+			expectedStatus: http.StatusBadRequest,
+			mockError:      utils.NewCustomUserError("Cart is empty"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/cart/checkout", nil)
+			req = req.WithContext(middleware.ContextWithUser(req.Context(), 7, usecase.RoleCustomer))
+			w := httptest.NewRecorder()
+
+			var output *usecase.CreateOrderOutput
+			if tt.mockError == nil {
+				output = &usecase.CreateOrderOutput{OrderID: 1, Status: usecase.OrderStatusPending}
+			}
+			mockCartUseCase.EXPECT().Checkout(gomock.Any(), int64(7)).Return(output, tt.mockError)
+
+			handler.CheckoutCartHandler(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Result().StatusCode)
+		})
+	}
+}
+
 func TestHealthCheckHandler(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
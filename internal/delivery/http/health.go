@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/masatrio/bookstore-api/internal/health"
+)
+
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates the handler for the liveness and readiness probes.
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
+
+// LivezHandler reports that the process is up. It does not check dependencies, so that an
+// outage of the database does not get every replica restarted.
+func (h *HealthHandler) LivezHandler(w http.ResponseWriter, r *http.Request) {
+	jsonResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// ReadyzHandler reports whether the service can take traffic, with the status and latency of
+// each dependency.
+func (h *HealthHandler) ReadyzHandler(w http.ResponseWriter, r *http.Request) {
+	report, ready := h.registry.Check(r.Context())
+
+	status := http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	jsonResponse(w, status, report)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	domainhealth "github.com/masatrio/bookstore-api/internal/domain/health"
+	"github.com/masatrio/bookstore-api/internal/health"
+)
+
+func TestReadyzHandler(t *testing.T) {
+	var dbErr error
+	registry := health.NewRegistry(time.Second)
+	registry.Register("postgres", domainhealth.HealthCheckerFunc(func(ctx context.Context) error { return dbErr }))
+	handler := NewHealthHandler(registry)
+
+	ready := func() (int, domainhealth.Report) {
+		w := httptest.NewRecorder()
+		handler.ReadyzHandler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+		var report domainhealth.Report
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+		return w.Code, report
+	}
+
+	status, report := ready()
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, domainhealth.StatusOK, report.Components["postgres"].Status)
+
+	dbErr = errors.New("connection refused")
+	status, report = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, domainhealth.StatusDown, report.Status)
+	assert.Equal(t, "connection refused", report.Components["postgres"].Error)
+
+	dbErr = nil
+	registry.MarkShuttingDown()
+	status, report = ready()
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, domainhealth.StatusShuttingDown, report.Status)
+
+	w := httptest.NewRecorder()
+	handler.LivezHandler(w, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type requestLogKey struct{}
+
+// requestLog collects details that inner middlewares learn about a request, since their context
+// changes are not visible to AccessLog.
+type requestLog struct {
+	userID      int64
+	spanContext trace.SpanContext
+}
+
+// AccessLog logs one line per request with its method, route template, status, latency and user ID.
+// It must run outside of OTelMiddleware and JWTMiddleware, which report the span and user to it.
+func AccessLog(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			entry := &requestLog{}
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+			ctx := context.WithValue(r.Context(), requestLogKey{}, entry)
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			attrs := []slog.Attr{
+				slog.String("method", r.Method),
+				slog.String("route", routeTemplate(r)),
+				slog.Int("status", recorder.status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			}
+			if entry.userID != 0 {
+				attrs = append(attrs, slog.Int64("user_id", entry.userID))
+			}
+
+			level := slog.LevelInfo
+			if recorder.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+			logger.LogAttrs(logContext(ctx), level, "request completed", attrs...)
+		})
+	}
+}
+
+// routeTemplate returns the path template of the matched route, such as /api/v1/books/{id}, so
+// that requests for different IDs are grouped together.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return r.URL.Path
+}
+
+// logContext returns ctx with the request span reported by OTelMiddleware, if ctx has none.
+func logContext(ctx context.Context) context.Context {
+	entry, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, entry.spanContext)
+}
+
+// reportUser records the authenticated user for the access log.
+func reportUser(ctx context.Context, userID int64) {
+	if entry, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		entry.userID = userID
+	}
+}
+
+// reportSpan records the request span for the access log.
+func reportSpan(ctx context.Context, spanContext trace.SpanContext) {
+	if entry, ok := ctx.Value(requestLogKey{}).(*requestLog); ok {
+		entry.spanContext = spanContext
+	}
+}
+
+// statusRecorder passes a response through while keeping its status.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
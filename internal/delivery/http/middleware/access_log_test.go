@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/masatrio/bookstore-api/utils"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestAccessLog(t *testing.T) {
+	var logs bytes.Buffer
+	logger, err := utils.NewLogger(&logs, "info")
+	assert.NoError(t, err)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}})
+
+	r := mux.NewRouter()
+	r.Use(RequestID, AccessLog(logger))
+	r.HandleFunc("/api/v1/books/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		// Stand-ins for what OTelMiddleware and JWTMiddleware report.
+		reportSpan(r.Context(), spanContext)
+		ContextWithUser(r.Context(), 7, "customer")
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/books/42", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, "req-1", rr.Header().Get(RequestIDHeader))
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+	assert.Equal(t, slog.LevelInfo.String(), record["level"])
+	assert.Equal(t, http.MethodGet, record["method"])
+	assert.Equal(t, "/api/v1/books/{id:[0-9]+}", record["route"])
+	assert.Equal(t, float64(http.StatusNotFound), record["status"])
+	assert.Equal(t, float64(7), record["user_id"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, spanContext.TraceID().String(), record["trace_id"])
+	assert.Contains(t, record, "latency_ms")
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID, ok := utils.GetRequestIDFromContext(r.Context())
+		assert.True(t, ok)
+		assert.Equal(t, w.Header().Get(RequestIDHeader), requestID)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set(RequestIDHeader, "bad\nid")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.NotEmpty(t, rr.Header().Get(RequestIDHeader))
+	assert.NotEqual(t, "bad\nid", rr.Header().Get(RequestIDHeader))
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	trusted := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		realIP       string
+		expectedIP   string
+	}{
+		{name: "untrusted peer ignores headers", remoteAddr: "203.0.113.7:1234", forwardedFor: []string{"198.51.100.1"}, realIP: "198.51.100.2", expectedIP: "203.0.113.7"},
+		{name: "trusted peer without headers", remoteAddr: "10.0.0.1:1234", expectedIP: "10.0.0.1"},
+		{name: "trusted peer forwards client", remoteAddr: "10.0.0.1:1234", forwardedFor: []string{"198.51.100.1"}, expectedIP: "198.51.100.1"},
+		{name: "spoofed entries left of the client are skipped", remoteAddr: "10.0.0.1:1234", forwardedFor: []string{"1.2.3.4, 198.51.100.1", "10.0.0.2"}, expectedIP: "198.51.100.1"},
+		{name: "malformed entry stops at last trusted hop", remoteAddr: "10.0.0.1:1234", forwardedFor: []string{"198.51.100.1, garbage, 10.0.0.2"}, expectedIP: "10.0.0.2"},
+		{name: "real ip from trusted peer", remoteAddr: "10.0.0.1:1234", realIP: "198.51.100.2", expectedIP: "198.51.100.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := TrustedProxies(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, tt.expectedIP, got)
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Deadline bounds each request's context by timeout. Database calls and other work that
+// honour the context are cancelled once it expires, and the handler reports the timeout
+// instead of holding the connection open past the server's write timeout. A non-positive
+// timeout disables the deadline.
+func Deadline(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeadline(t *testing.T) {
+	var ctxErr error
+	handler := Deadline(10 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		assert.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(10*time.Millisecond), deadline, 10*time.Millisecond)
+
+		<-r.Context().Done()
+		ctxErr = r.Context().Err()
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/books", nil))
+
+	assert.ErrorIs(t, ctxErr, context.DeadlineExceeded)
+	assert.Equal(t, http.StatusGatewayTimeout, rr.Code)
+}
+
+func TestDeadline_Disabled(t *testing.T) {
+	handler := Deadline(0)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, ok := r.Context().Deadline()
+		assert.False(t, ok)
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/books", nil))
+}
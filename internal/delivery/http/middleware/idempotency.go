@@ -0,0 +1,138 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// IdempotencyKeyHeader is the request header clients set to make a POST safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const maxIdempotencyKeyLength = 255
+
+// Idempotency replays the stored response when a request is retried with the same Idempotency-Key.
+// Reusing a key with a different request is rejected with 422. Requests without the header pass
+// straight through. It must run after JWTMiddleware, since keys are scoped to the user.
+func Idempotency(store repository.IdempotencyRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "IdempotencyMiddleware")
+			defer span.End()
+
+			if len(key) > maxIdempotencyKeyLength {
+				span.SetStatus(codes.Error, "Idempotency key too long")
+				http.Error(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+				return
+			}
+
+			userID, ok := GetUserIDFromContext(ctx)
+			if !ok {
+				span.SetStatus(codes.Error, "User ID not found in context")
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				span.SetStatus(codes.Error, "Failed to read request body")
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			requestHash := hashRequest(r, body)
+
+			reserved, err := store.Reserve(ctx, userID, key, requestHash)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "Failed to reserve idempotency key")
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+
+			if !reserved {
+				record, err := store.Get(ctx, userID, key)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "Failed to get idempotency key")
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+
+				switch {
+				case record != nil && record.RequestHash != requestHash:
+					span.SetStatus(codes.Error, "Idempotency key reused with a different request")
+					http.Error(w, "Idempotency-Key has already been used with a different request", http.StatusUnprocessableEntity)
+				case record == nil || record.StatusCode == 0:
+					span.SetStatus(codes.Error, "Idempotent request in progress")
+					http.Error(w, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+				default:
+					span.SetStatus(codes.Ok, "Replayed idempotent response")
+					w.Header().Set("Idempotent-Replayed", "true")
+					if len(record.ResponseBody) > 0 {
+						w.Header().Set("Content-Type", "application/json")
+					}
+					w.WriteHeader(record.StatusCode)
+					w.Write(record.ResponseBody)
+				}
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			// The client may have gone away, but the outcome must still be recorded.
+			ctx = context.WithoutCancel(ctx)
+
+			// Server errors are not stored so that the client can retry them.
+			if recorder.status >= http.StatusInternalServerError {
+				if err := store.Release(ctx, userID, key); err != nil {
+					span.RecordError(err)
+				}
+				return
+			}
+
+			if err := store.Complete(ctx, userID, key, recorder.status, recorder.body.Bytes()); err != nil {
+				span.RecordError(err)
+			}
+		})
+	}
+}
+
+// hashRequest fingerprints the method, URI and body of a request.
+func hashRequest(r *http.Request, body []byte) string {
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.RequestURI()+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder passes a response through while keeping a copy of its status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	rr.status = status
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeIdempotencyStore struct {
+	records map[string]*repository.IdempotencyRecord
+}
+
+func (s *fakeIdempotencyStore) Reserve(ctx context.Context, userID int64, key, requestHash string) (bool, error) {
+	if _, ok := s.records[key]; ok {
+		return false, nil
+	}
+	s.records[key] = &repository.IdempotencyRecord{UserID: userID, Key: key, RequestHash: requestHash}
+	return true, nil
+}
+
+func (s *fakeIdempotencyStore) Get(ctx context.Context, userID int64, key string) (*repository.IdempotencyRecord, error) {
+	return s.records[key], nil
+}
+
+func (s *fakeIdempotencyStore) Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error {
+	s.records[key].StatusCode = statusCode
+	s.records[key].ResponseBody = body
+	return nil
+}
+
+func (s *fakeIdempotencyStore) Release(ctx context.Context, userID int64, key string) error {
+	delete(s.records, key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	store := &fakeIdempotencyStore{records: map[string]*repository.IdempotencyRecord{}}
+
+	calls := 0
+	status := http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+		w.Write([]byte(`{"order_id":1}`))
+	})
+	handler := Idempotency(store)(next)
+
+	send := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", bytes.NewBufferString(body))
+		req = req.WithContext(ContextWithUser(req.Context(), 7, "customer"))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("First Request", func(t *testing.T) {
+		rr := send("key-1", `{"items":[]}`)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		rr := send("key-1", `{"items":[]}`)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, `{"order_id":1}`, rr.Body.String())
+		assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Different Body", func(t *testing.T) {
+		rr := send("key-1", `{"items":[{"book_id":2}]}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Without Key", func(t *testing.T) {
+		send("", `{"items":[]}`)
+		send("", `{"items":[]}`)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Server Error Is Not Stored", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		send("key-2", `{}`)
+		status = http.StatusCreated
+		rr := send("key-2", `{}`)
+		assert.Equal(t, http.StatusCreated, rr.Code)
+		assert.Equal(t, 5, calls)
+	})
+
+	t.Run("In Progress", func(t *testing.T) {
+		store.records["key-3"] = &repository.IdempotencyRecord{UserID: 7, Key: "key-3", RequestHash: hashRequest(httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil), []byte(`{}`))}
+		rr := send("key-3", `{}`)
+		assert.Equal(t, http.StatusConflict, rr.Code)
+	})
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
 	"github.com/masatrio/bookstore-api/utils"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -14,54 +15,92 @@ import (
 
 type contextKey string
 
-const userIDKey contextKey = "userID"
-
-// JWTMiddleware checks the validity of the JWT token in the Authorization header.
-func JWTMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "JWTMiddleware")
-		defer span.End()
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			span.SetStatus(codes.Error, "Authorization header missing")
-			http.Error(w, "Authorization header missing", http.StatusUnauthorized)
-			return
-		}
-
-		bearerToken := strings.Split(authHeader, "Bearer ")
-		if len(bearerToken) != 2 {
-			span.SetStatus(codes.Error, "Invalid Authorization header format")
-			http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
-			return
-		}
-		tokenString := bearerToken[1]
-
-		secretKey := config.LoadConfig().JWT.Secret
-
-		token, err := jwt.ParseWithClaims(tokenString, &utils.Claims{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, http.ErrNotSupported
+const (
+	userIDKey   contextKey = "userID"
+	userRoleKey contextKey = "userRole"
+	claimsKey   contextKey = "claims"
+)
+
+// JWTMiddleware checks the validity of the JWT token in the Authorization header and rejects
+// tokens whose ID has been put on the denylist.
+func JWTMiddleware(denylist cache.TokenDenylist) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "JWTMiddleware")
+			defer span.End()
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" {
+				span.SetStatus(codes.Error, "Authorization header missing")
+				http.Error(w, "Authorization header missing", http.StatusUnauthorized)
+				return
+			}
+
+			bearerToken := strings.Split(authHeader, "Bearer ")
+			if len(bearerToken) != 2 {
+				span.SetStatus(codes.Error, "Invalid Authorization header format")
+				http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+				return
+			}
+			tokenString := bearerToken[1]
+
+			secretKey := config.LoadConfig().JWT.Secret
+
+			token, err := jwt.ParseWithClaims(tokenString, &utils.Claims{}, func(token *jwt.Token) (interface{}, error) {
+				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+					return nil, http.ErrNotSupported
+				}
+				return []byte(secretKey), nil
+			})
+
+			if err != nil || !token.Valid {
+				span.SetStatus(codes.Error, "Invalid or expired token")
+				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+				return
+			}
+
+			claims, ok := token.Claims.(*utils.Claims)
+			if !ok {
+				span.SetStatus(codes.Error, "Invalid token claims")
+				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+				return
 			}
-			return []byte(secretKey), nil
-		})
 
-		if err != nil || !token.Valid {
-			span.SetStatus(codes.Error, "Invalid or expired token")
-			http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-			return
-		}
+			// Tokens issued before revocation support have no ID and simply run until they expire.
+			if claims.Id != "" {
+				denied, err := denylist.IsDenied(ctx, claims.Id)
+				if err != nil {
+					span.RecordError(err)
+					span.SetStatus(codes.Error, "Failed to check token denylist")
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+					return
+				}
+				if denied {
+					span.SetStatus(codes.Error, "Token has been revoked")
+					http.Error(w, "Token has been revoked", http.StatusUnauthorized)
+					return
+				}
+			}
 
-		if claims, ok := token.Claims.(*utils.Claims); ok && token.Valid {
-			ctx = context.WithValue(r.Context(), userIDKey, claims.UserID)
+			ctx = ContextWithClaims(r.Context(), claims)
 			r = r.WithContext(ctx)
-		} else {
-			span.SetStatus(codes.Error, "Invalid token claims")
-			http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-			return
-		}
-
-		next.ServeHTTP(w, r)
-	})
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ContextWithUser returns a copy of ctx carrying the authenticated user's ID and role. The user
+// is also reported to the access log.
+func ContextWithUser(ctx context.Context, userID int64, role string) context.Context {
+	reportUser(ctx, userID)
+	ctx = context.WithValue(ctx, userIDKey, userID)
+	return context.WithValue(ctx, userRoleKey, role)
+}
+
+// ContextWithClaims returns a copy of ctx carrying the user and access token details from claims.
+func ContextWithClaims(ctx context.Context, claims *utils.Claims) context.Context {
+	ctx = ContextWithUser(ctx, claims.UserID, claims.Role)
+	return context.WithValue(ctx, claimsKey, claims)
 }
 
 // GetUserIDFromContext retrieves the user ID from the context.
@@ -69,3 +108,15 @@ func GetUserIDFromContext(ctx context.Context) (int64, bool) {
 	userID, ok := ctx.Value(userIDKey).(int64)
 	return userID, ok
 }
+
+// GetClaimsFromContext retrieves the claims of the access token used for the request.
+func GetClaimsFromContext(ctx context.Context) (*utils.Claims, bool) {
+	claims, ok := ctx.Value(claimsKey).(*utils.Claims)
+	return claims, ok
+}
+
+// GetUserRoleFromContext retrieves the user role from the context.
+func GetUserRoleFromContext(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(userRoleKey).(string)
+	return role, ok
+}
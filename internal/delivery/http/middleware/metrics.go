@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Metrics records the request count, duration and in-flight requests per route template and
+// status. Requests are labelled by template rather than path to keep the label set small.
+func Metrics(meter metric.Meter) func(http.Handler) http.Handler {
+	// Instrument errors only come from invalid names, and the returned no-op instruments are safe to use.
+	requests, _ := meter.Int64Counter("http.server.requests",
+		metric.WithDescription("Number of HTTP requests handled."),
+	)
+	duration, _ := meter.Float64Histogram("http.server.request.duration",
+		metric.WithDescription("Duration of HTTP requests."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10),
+	)
+	inFlight, _ := meter.Int64UpDownCounter("http.server.active_requests",
+		metric.WithDescription("Number of HTTP requests in flight."),
+	)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			start := time.Now()
+			route := attribute.String("http.route", routeTemplate(r))
+			method := attribute.String("http.method", r.Method)
+
+			inFlight.Add(ctx, 1, metric.WithAttributes(method, route))
+			defer inFlight.Add(ctx, -1, metric.WithAttributes(method, route))
+
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			attrs := metric.WithAttributes(method, route, attribute.String("http.status_code", strconv.Itoa(recorder.status)))
+			requests.Add(ctx, 1, attrs)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		})
+	}
+}
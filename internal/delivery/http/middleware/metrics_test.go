@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	r := mux.NewRouter()
+	r.Use(Metrics(meter))
+	r.HandleFunc("/api/v1/books/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/api/v1/books/1", "/api/v1/books/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	requests := metrics["http.server.requests"].Data.(metricdata.Sum[int64])
+	assert.Len(t, requests.DataPoints, 1)
+	assert.Equal(t, int64(2), requests.DataPoints[0].Value)
+	route, _ := requests.DataPoints[0].Attributes.Value(attribute.Key("http.route"))
+	assert.Equal(t, "/api/v1/books/{id:[0-9]+}", route.AsString())
+	status, _ := requests.DataPoints[0].Attributes.Value(attribute.Key("http.status_code"))
+	assert.Equal(t, "404", status.AsString())
+
+	duration := metrics["http.server.request.duration"].Data.(metricdata.Histogram[float64])
+	assert.Equal(t, uint64(2), duration.DataPoints[0].Count)
+
+	inFlight := metrics["http.server.active_requests"].Data.(metricdata.Sum[int64])
+	assert.Equal(t, int64(0), inFlight.DataPoints[0].Value)
+}
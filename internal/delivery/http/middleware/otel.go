@@ -3,17 +3,21 @@ package middleware
 import (
 	"net/http"
 
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// OTelMiddleware applies OpenTelemetry tracing to the handler.
+// OTelMiddleware applies OpenTelemetry tracing to the handler. A trace started by the caller
+// (the traceparent and baggage headers) is continued rather than starting a new one.
 func OTelMiddleware(tracer trace.Tracer) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-
-			ctx, span := tracer.Start(r.Context(), r.Method+" "+r.URL.Path)
+			ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path, trace.WithSpanKind(trace.SpanKindServer))
 			defer span.End()
+			reportSpan(ctx, span.SpanContext())
 
 			span.SetAttributes(
 				attribute.String("http.method", r.Method),
@@ -9,8 +9,10 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
@@ -57,3 +59,27 @@ func TestOTelMiddleware(t *testing.T) {
 	}
 
 }
+
+// TestOTelMiddlewareContinuesTrace tests that a traceparent sent by the caller is continued.
+func TestOTelMiddlewareContinuesTrace(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	handler := OTelMiddleware(tp.Tracer("test-tracer"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if got := spans[0].SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("expected the caller's trace ID, got %s", got)
+	}
+	if got := spans[0].Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("expected the caller's span as parent, got %s", got)
+	}
+}
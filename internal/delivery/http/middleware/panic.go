@@ -1,25 +1,28 @@
 package middleware
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"runtime"
 )
 
-// PanicRecoveryMiddleware recovers from panics and writes a 500 if there was one.
-func PanicRecoveryMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if rec := recover(); rec != nil {
-				log.Printf("Panic recovered: %v\n", rec)
+// PanicRecoveryMiddleware recovers from panics, logs them with their stack trace and writes a 500.
+func PanicRecoveryMiddleware(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					buf := make([]byte, 1<<16)
+					stackSize := runtime.Stack(buf, false)
+					logger.ErrorContext(logContext(r.Context()), "Panic recovered",
+						slog.Any("panic", rec),
+						slog.String("stack", string(buf[:stackSize])),
+					)
 
-				buf := make([]byte, 1<<16)
-				stackSize := runtime.Stack(buf, true)
-				log.Printf("Stack trace:\n%s\n", buf[:stackSize])
-
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			}
-		}()
-		next.ServeHTTP(w, r)
-	})
+					http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
 }
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"bytes"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -14,7 +16,9 @@ func TestPanicRecoveryMiddleware(t *testing.T) {
 		panic("test panic")
 	})
 
-	recoveryMiddleware := PanicRecoveryMiddleware(panickingHandler)
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	recoveryMiddleware := PanicRecoveryMiddleware(logger)(panickingHandler)
 
 	req := httptest.NewRequest(http.MethodGet, "http://bookstore", nil)
 	rr := httptest.NewRecorder()
@@ -24,4 +28,5 @@ func TestPanicRecoveryMiddleware(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 
 	assert.Contains(t, rr.Body.String(), "Internal Server Error")
+	assert.Contains(t, logs.String(), "test panic")
 }
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimit applies a token bucket per client to the named route and rejects requests over the
+// limit with 429. Authenticated clients are keyed by user ID and anonymous ones by IP, so on
+// protected routes it must run after JWTMiddleware. If the store fails, requests are let through.
+func RateLimit(store cache.RateLimitStore, route string, limit config.RateLimit) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RateLimitMiddleware")
+			defer span.End()
+
+			client := "ip:" + ClientIP(r)
+			if userID, ok := GetUserIDFromContext(ctx); ok {
+				client = "user:" + strconv.FormatInt(userID, 10)
+			}
+
+			result, err := store.Take(ctx, route+":"+client, limit.Rate, limit.Burst)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "Failed to check rate limit")
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+
+			if !result.Allowed {
+				span.AddEvent("rate_limited", trace.WithAttributes(
+					attribute.String("rate_limit.route", route),
+					attribute.String("rate_limit.client", client),
+				))
+				span.SetStatus(codes.Error, "Rate limit exceeded")
+				w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ceilSeconds formats a duration as whole seconds, rounded up.
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
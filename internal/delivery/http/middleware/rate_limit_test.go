@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RateLimit(memory.NewMemoryRateLimitStore(), "books.list", config.RateLimit{Rate: 1, Burst: 2})(next)
+
+	send := func(remoteAddr string, userID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/books", nil)
+		req.RemoteAddr = remoteAddr
+		if userID != 0 {
+			req = req.WithContext(ContextWithUser(req.Context(), userID, "customer"))
+		}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := send("192.0.2.1:1234", 0)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = send("192.0.2.1:5678", 0)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+
+	rr = send("192.0.2.1:1234", 0)
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+	assert.Equal(t, "2", rr.Header().Get("RateLimit-Reset"))
+
+	// Authenticated requests from the same IP get their own bucket per user.
+	rr = send("192.0.2.1:1234", 7)
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = send("192.0.2.2:1234", 0)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
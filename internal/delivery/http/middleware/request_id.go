@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// RequestIDHeader carries the request ID on requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID puts a request ID into the context and echoes it in the response. An ID sent by the
+// client or a proxy is reused so that logs can be correlated across services.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength || !isPrintableASCII(requestID) {
+			token, err := utils.NewRandomToken(16)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+				return
+			}
+			requestID = token
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		next.ServeHTTP(w, r.WithContext(utils.ContextWithRequestID(r.Context(), requestID)))
+	})
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
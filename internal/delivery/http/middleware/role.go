@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequireRole only lets requests through when the authenticated user has one of the given roles.
+// It must run after JWTMiddleware, which puts the role into the context.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, span := trace.SpanFromContext(r.Context()).TracerProvider().Tracer("").Start(r.Context(), "RequireRole")
+			defer span.End()
+
+			role, ok := GetUserRoleFromContext(r.Context())
+			if !ok {
+				span.SetStatus(codes.Error, "User role not found in context")
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			span.SetStatus(codes.Error, "Insufficient role")
+			http.Error(w, "Forbidden", http.StatusForbidden)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		expectedStatus int
+	}{
+		{
+			name:           "Allowed Role",
+			ctx:            context.WithValue(context.Background(), userRoleKey, "admin"),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Other Role",
+			ctx:            context.WithValue(context.Background(), userRoleKey, "customer"),
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "Role Not Present in Context",
+			ctx:            context.Background(),
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+			rr := httptest.NewRecorder()
+
+			RequireRole("admin")(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+		})
+	}
+}
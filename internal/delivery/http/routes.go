@@ -1,50 +1,197 @@
 package http
 
 import (
-	"log"
+	"context"
+	"log/slog"
 	"net/http"
+	"os"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/masatrio/bookstore-api/config"
 	"github.com/masatrio/bookstore-api/internal/delivery/http/middleware"
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	domainhealth "github.com/masatrio/bookstore-api/internal/domain/health"
+	"github.com/masatrio/bookstore-api/internal/domain/mailer"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	domainsearch "github.com/masatrio/bookstore-api/internal/domain/search"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/internal/email"
+	"github.com/masatrio/bookstore-api/internal/health"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/cached"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/memory"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/redis"
 	"github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+	"github.com/masatrio/bookstore-api/internal/search"
 	"github.com/masatrio/bookstore-api/internal/usecase/book"
+	"github.com/masatrio/bookstore-api/internal/usecase/cart"
+	"github.com/masatrio/bookstore-api/internal/usecase/category"
 	"github.com/masatrio/bookstore-api/internal/usecase/order"
 	"github.com/masatrio/bookstore-api/internal/usecase/user"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/trace"
 )
 
-// BasicHandler applies the necessary middlewares to a public handler.
+// idempotencyKeyTTL is how long a stored Idempotency-Key response is replayed.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// defaultIdempotencyLease bounds how long an unfinished Idempotency-Key reservation blocks
+// retries when no server timeouts are configured.
+const defaultIdempotencyLease = time.Minute
+
+// idempotencyLeaseMargin is added to the server timeouts so that a request finishing right at its
+// deadline, and storing its response, is not overtaken by a retry.
+const idempotencyLeaseMargin = 30 * time.Second
+
+// BasicHandler applies the necessary middlewares to a public handler. Request IDs, access logging
+// and panic recovery are applied to every route by InitRoutes.
 func BasicHandler(handlerFunc http.HandlerFunc, tracer trace.Tracer) http.Handler {
-	return middleware.PanicRecoveryMiddleware(middleware.OTelMiddleware(tracer)(handlerFunc))
+	return middleware.OTelMiddleware(tracer)(handlerFunc)
 }
 
 // ProtectedHandler applies JWT authentication and other middlewares to protected handlers.
-func ProtectedHandler(handlerFunc http.HandlerFunc, tracer trace.Tracer) http.Handler {
-	return BasicHandler(http.HandlerFunc(middleware.JWTMiddleware(handlerFunc).ServeHTTP), tracer)
+func ProtectedHandler(handlerFunc http.HandlerFunc, tracer trace.Tracer, denylist cache.TokenDenylist) http.Handler {
+	return BasicHandler(http.HandlerFunc(middleware.JWTMiddleware(denylist)(handlerFunc).ServeHTTP), tracer)
+}
+
+// RoleProtectedHandler applies JWT authentication and restricts access to users with one of the given roles.
+func RoleProtectedHandler(handlerFunc http.HandlerFunc, tracer trace.Tracer, denylist cache.TokenDenylist, roles ...string) http.Handler {
+	return ProtectedHandler(middleware.RequireRole(roles...)(handlerFunc).ServeHTTP, tracer, denylist)
 }
 
 // NewApp initializes the app with the necessary dependencies and starts the server.
-func InitAPP(config *config.Config, tracer trace.Tracer) http.Handler {
+// metricsHandler serves the Prometheus scrape page and may be nil when metrics are pushed instead.
+// The database and Redis are added to the readiness checks in registry.
+func InitAPP(config *config.Config, tracer trace.Tracer, logger *slog.Logger, metricsHandler http.Handler, registry *health.Registry) http.Handler {
 	db, err := postgresql.NewDatabase(config.Database)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		logger.Error("Failed to connect to database", slog.Any("error", err))
+		os.Exit(1)
 	}
 
-	bookRepo := postgresql.NewPostgresBookRepository(db)
-	userRepo := postgresql.NewPostgresUserRepository(db)
-	orderRepo := postgresql.NewPostgresOrderRepository(db)
-	orderItemRepo := postgresql.NewPostgresOrderItemRepository(db)
+	caches := newCaches(config, logger)
+
+	registry.Register("postgres", postgresql.NewHealthChecker(db))
+	if caches.health != nil {
+		registry.Register("redis", caches.health)
+	}
 
-	repo := postgresql.NewRepository(db, bookRepo, orderRepo, orderItemRepo, userRepo)
+	dbTimeout := time.Duration(config.Database.Timeout) * time.Second
 
-	userUsecase := user.NewUserUseCase(repo, config.JWT.Secret, time.Duration(config.JWT.Expiry)*time.Second)
-	bookUsecase := book.NewBookUseCase(repo)
+	postgresBookRepo := postgresql.NewPostgresBookRepository(db, dbTimeout)
+	bookRepo := cached.NewCachedBookRepository(postgresBookRepo, caches.books)
+	userRepo := postgresql.NewPostgresUserRepository(db, dbTimeout)
+	orderRepo := postgresql.NewPostgresOrderRepository(db, dbTimeout)
+	orderItemRepo := postgresql.NewPostgresOrderItemRepository(db, dbTimeout)
+	historyRepo := postgresql.NewPostgresOrderStatusHistoryRepository(db, dbTimeout)
+	cartRepo := postgresql.NewPostgresCartRepository(db, dbTimeout)
+	categoryRepo := cached.NewCachedCategoryRepository(postgresql.NewPostgresCategoryRepository(db, dbTimeout), caches.books)
+	authorRepo := cached.NewCachedAuthorRepository(postgresql.NewPostgresAuthorRepository(db, dbTimeout), caches.books)
+	publisherRepo := cached.NewCachedPublisherRepository(postgresql.NewPostgresPublisherRepository(db, dbTimeout), caches.books)
+	refreshRepo := postgresql.NewPostgresRefreshTokenRepository(db, dbTimeout)
+	userTokenRepo := postgresql.NewPostgresUserTokenRepository(db, dbTimeout)
+	idempotencyRepo := postgresql.NewPostgresIdempotencyRepository(db, dbTimeout, idempotencyKeyTTL, idempotencyLease(config))
+
+	repo := postgresql.NewRepository(db, dbTimeout, bookRepo, orderRepo, orderItemRepo, historyRepo, userRepo, cartRepo, categoryRepo, authorRepo, publisherRepo, refreshRepo, userTokenRepo)
+
+	userUsecase := user.NewUserUseCase(repo, caches.denylist, caches.loginAttempts, newMailer(config, logger), config.JWT.Secret, time.Duration(config.JWT.Expiry)*time.Second)
+	bookUsecase := book.NewBookUseCase(repo, newSearchIndex(config, logger, postgresBookRepo))
 	orderUsecase := order.NewOrderUseCase(repo)
+	cartUsecase := cart.NewCartUseCase(repo, orderUsecase)
+	categoryUsecase := category.NewCategoryUseCase(repo)
+
+	return InitRoutes(tracer, config, userUsecase, bookUsecase, orderUsecase, cartUsecase, categoryUsecase, idempotencyRepo, caches.denylist, caches.rateLimits, logger, metricsHandler, registry)
+}
+
+// idempotencyLease returns how long a request may hold its Idempotency-Key before a retry can
+// take it over: no request outlives the server's request and write timeouts, plus a margin.
+func idempotencyLease(config *config.Config) time.Duration {
+	seconds := max(config.Server.RequestTimeout, config.Server.WriteTimeout)
+	if seconds <= 0 {
+		return defaultIdempotencyLease
+	}
+	return time.Duration(seconds)*time.Second + idempotencyLeaseMargin
+}
+
+// appCaches holds the Redis- or memory-backed stores shared by the app.
+type appCaches struct {
+	books         cache.BookCache
+	denylist      cache.TokenDenylist
+	loginAttempts cache.LoginAttemptStore
+	rateLimits    cache.RateLimitStore
+	health        domainhealth.HealthChecker // nil for the in-process caches
+}
+
+// newCaches builds the caches for the configured driver, falling back to the in-process
+// implementations.
+func newCaches(config *config.Config, logger *slog.Logger) appCaches {
+	ttl := time.Duration(config.Cache.TTL) * time.Second
+
+	if config.Cache.Driver != "redis" {
+		return appCaches{
+			books:         memory.NewMemoryBookCache(ttl),
+			denylist:      memory.NewMemoryTokenDenylist(),
+			loginAttempts: memory.NewMemoryLoginAttemptStore(),
+			rateLimits:    memory.NewMemoryRateLimitStore(),
+		}
+	}
+
+	client, err := redis.NewClient(config.Redis)
+	if err != nil {
+		logger.Error("Failed to create Redis client", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	return appCaches{
+		books:         redis.NewRedisBookCache(client, ttl),
+		denylist:      redis.NewRedisTokenDenylist(client),
+		loginAttempts: redis.NewRedisLoginAttemptStore(client),
+		rateLimits:    redis.NewRedisRateLimitStore(client),
+		health:        redis.NewHealthChecker(client),
+	}
+}
 
-	return InitRoutes(tracer, config, userUsecase, bookUsecase, orderUsecase)
+// newSearchIndex builds the search index for the configured driver. The postgres driver needs
+// no separate index and returns nil. The embedded index loads its snapshot, or is rebuilt from
+// the database when there is none.
+func newSearchIndex(config *config.Config, logger *slog.Logger, books repository.BookRepository) domainsearch.SearchIndex {
+	if config.Search.Driver != "memory" {
+		return nil
+	}
+
+	index, err := search.Open(config.Search.IndexPath)
+	if err != nil {
+		logger.Error("Failed to open search index", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	if index.Len() == 0 {
+		count, err := search.Reindex(context.Background(), books, index)
+		if err != nil {
+			logger.Error("Failed to build search index", slog.Any("error", err))
+			os.Exit(1)
+		}
+		logger.Info("Built search index", slog.Int("books", count))
+	}
+
+	return index
+}
+
+// newMailer builds the mailer for the configured driver.
+func newMailer(config *config.Config, logger *slog.Logger) mailer.Mailer {
+	switch config.Mail.Driver {
+	case "smtp":
+		return email.NewSMTPMailer(config.Mail)
+	case "file":
+		m, err := email.NewFileMailer(config.Mail.From, config.Mail.FilePath)
+		if err != nil {
+			logger.Error("Failed to open mail file", slog.Any("error", err))
+			os.Exit(1)
+		}
+		return m
+	default:
+		return email.NewWriterMailer(config.Mail.From, os.Stdout)
+	}
 }
 
 // InitRoutes initializes the routes for the bookstore service.
@@ -54,26 +201,111 @@ func InitRoutes(
 	userUsecase usecase.UserUseCase,
 	bookUsecase usecase.BookUseCase,
 	orderUsecase usecase.OrderUseCase,
+	cartUsecase usecase.CartUseCase,
+	categoryUsecase usecase.CategoryUseCase,
+	idempotencyRepo repository.IdempotencyRepository,
+	denylist cache.TokenDenylist,
+	rateLimits cache.RateLimitStore,
+	logger *slog.Logger,
+	metricsHandler http.Handler,
+	registry *health.Registry,
 ) http.Handler {
 	r := mux.NewRouter()
+	r.Use(
+		middleware.RequestID,
+		middleware.TrustedProxies(config.Server.TrustedProxies),
+		middleware.AccessLog(logger),
+		middleware.Metrics(otel.Meter("github.com/masatrio/bookstore-api/internal/delivery/http")),
+		middleware.PanicRecoveryMiddleware(logger),
+		middleware.Deadline(time.Duration(config.Server.RequestTimeout)*time.Second),
+	)
 
-	handler := NewHandler(userUsecase, bookUsecase, orderUsecase)
+	handler := NewHandler(userUsecase, bookUsecase, orderUsecase, cartUsecase, categoryUsecase, config.Server.MaxPageLimit)
+
+	// idempotent lets clients safely retry a POST by sending an Idempotency-Key header.
+	idempotent := func(handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return middleware.Idempotency(idempotencyRepo)(handlerFunc).ServeHTTP
+	}
+
+	// limited applies the configured rate limit for the named route.
+	limited := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		if !config.RateLimit.Enabled {
+			return handlerFunc
+		}
+		return middleware.RateLimit(rateLimits, route, config.RateLimit.ForRoute(route))(handlerFunc).ServeHTTP
+	}
 
 	// Public routes
 	authRoutes := r.PathPrefix("/api/v1/auth").Subrouter()
-	authRoutes.HandleFunc("/register", BasicHandler(handler.RegisterHandler, tracer).ServeHTTP).Methods(http.MethodPost)
-	authRoutes.HandleFunc("/login", BasicHandler(handler.LoginHandler, tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/register", BasicHandler(limited("auth.register", handler.RegisterHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/login", BasicHandler(limited("auth.login", handler.LoginHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/refresh", BasicHandler(limited("auth.refresh", handler.RefreshHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/forgot-password", BasicHandler(limited("auth.forgot_password", handler.ForgotPasswordHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/reset-password", BasicHandler(limited("auth.reset_password", handler.ResetPasswordHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/verify-email", BasicHandler(limited("auth.verify_email", handler.VerifyEmailHandler), tracer).ServeHTTP).Methods(http.MethodPost)
+	authRoutes.HandleFunc("/logout", ProtectedHandler(limited("auth.logout", handler.LogoutHandler), tracer, denylist).ServeHTTP).Methods(http.MethodPost)
 
 	// Private routes with JWT middleware
 	bookRoutes := r.PathPrefix("/api/v1/books").Subrouter()
-	bookRoutes.HandleFunc("", ProtectedHandler(handler.ListBooksHandler, tracer).ServeHTTP).Methods(http.MethodGet)
+	bookRoutes.HandleFunc("", ProtectedHandler(limited("books.list", handler.ListBooksHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+	bookRoutes.HandleFunc("/{id:[0-9]+}", ProtectedHandler(limited("books.get", handler.GetBookHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+
+	// Admin-only catalog management routes
+	bookRoutes.HandleFunc("", RoleProtectedHandler(limited("books.create", idempotent(handler.CreateBookHandler)), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPost)
+	bookRoutes.HandleFunc("/{id:[0-9]+}", RoleProtectedHandler(limited("books.update", handler.UpdateBookHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPut, http.MethodPatch)
+	bookRoutes.HandleFunc("/{id:[0-9]+}", RoleProtectedHandler(limited("books.delete", handler.DeleteBookHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodDelete)
+	bookRoutes.HandleFunc("/{id:[0-9]+}/restock", RoleProtectedHandler(limited("books.restock", idempotent(handler.RestockBookHandler)), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPost)
+	bookRoutes.HandleFunc("/stock", RoleProtectedHandler(limited("books.stock", handler.ListStockHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodGet)
+	bookRoutes.HandleFunc("/{id:[0-9]+}/categories", RoleProtectedHandler(limited("books.set_categories", handler.SetBookCategoriesHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPut)
+	bookRoutes.HandleFunc("/{id:[0-9]+}/authors", RoleProtectedHandler(limited("books.set_authors", handler.SetBookAuthorsHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPut)
+
+	authorRoutes := r.PathPrefix("/api/v1/authors").Subrouter()
+	authorRoutes.HandleFunc("/{id:[0-9]+}/books", ProtectedHandler(limited("authors.books", handler.ListAuthorBooksHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+
+	publisherRoutes := r.PathPrefix("/api/v1/publishers").Subrouter()
+	publisherRoutes.HandleFunc("/{id:[0-9]+}/books", ProtectedHandler(limited("publishers.books", handler.ListPublisherBooksHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+
+	categoryRoutes := r.PathPrefix("/api/v1/categories").Subrouter()
+	categoryRoutes.HandleFunc("", ProtectedHandler(limited("categories.list", handler.ListCategoriesHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+	categoryRoutes.HandleFunc("/{id:[0-9]+}", ProtectedHandler(limited("categories.get", handler.GetCategoryHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+
+	// Admin-only category management routes
+	categoryRoutes.HandleFunc("", RoleProtectedHandler(limited("categories.create", idempotent(handler.CreateCategoryHandler)), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPost)
+	categoryRoutes.HandleFunc("/{id:[0-9]+}", RoleProtectedHandler(limited("categories.update", handler.UpdateCategoryHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPut)
+	categoryRoutes.HandleFunc("/{id:[0-9]+}", RoleProtectedHandler(limited("categories.delete", handler.DeleteCategoryHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodDelete)
 
 	orderRoutes := r.PathPrefix("/api/v1/orders").Subrouter()
-	orderRoutes.HandleFunc("", ProtectedHandler(handler.GetOrdersHandler, tracer).ServeHTTP).Methods(http.MethodGet)
-	orderRoutes.HandleFunc("", ProtectedHandler(handler.CreateOrderHandler, tracer).ServeHTTP).Methods(http.MethodPost)
+	orderRoutes.HandleFunc("", ProtectedHandler(limited("orders.list", handler.GetOrdersHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+	orderRoutes.HandleFunc("", ProtectedHandler(limited("orders.create", idempotent(handler.CreateOrderHandler)), tracer, denylist).ServeHTTP).Methods(http.MethodPost)
+	orderRoutes.HandleFunc("/{id:[0-9]+}", ProtectedHandler(limited("orders.get", handler.GetOrderHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+	orderRoutes.HandleFunc("/{id:[0-9]+}/cancel", ProtectedHandler(limited("orders.cancel", idempotent(handler.CancelOrderHandler)), tracer, denylist).ServeHTTP).Methods(http.MethodPost)
+
+	// Admin-only order administration routes
+	orderRoutes.HandleFunc("/{id:[0-9]+}/status", RoleProtectedHandler(limited("orders.update_status", handler.UpdateOrderStatusHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodPatch)
+
+	cartRoutes := r.PathPrefix("/api/v1/cart").Subrouter()
+	cartRoutes.HandleFunc("/items", ProtectedHandler(limited("cart.get", handler.GetCartHandler), tracer, denylist).ServeHTTP).Methods(http.MethodGet)
+	cartRoutes.HandleFunc("/items", ProtectedHandler(limited("cart.add_item", idempotent(handler.AddCartItemHandler)), tracer, denylist).ServeHTTP).Methods(http.MethodPost)
+	cartRoutes.HandleFunc("/items", ProtectedHandler(limited("cart.clear", handler.ClearCartHandler), tracer, denylist).ServeHTTP).Methods(http.MethodDelete)
+	cartRoutes.HandleFunc("/items/{id:[0-9]+}", ProtectedHandler(limited("cart.update_item", handler.UpdateCartItemHandler), tracer, denylist).ServeHTTP).Methods(http.MethodPatch)
+	cartRoutes.HandleFunc("/items/{id:[0-9]+}", ProtectedHandler(limited("cart.remove_item", handler.RemoveCartItemHandler), tracer, denylist).ServeHTTP).Methods(http.MethodDelete)
+	cartRoutes.HandleFunc("/checkout", ProtectedHandler(limited("cart.checkout", idempotent(handler.CheckoutCartHandler)), tracer, denylist).ServeHTTP).Methods(http.MethodPost)
+
+	// Admin-only account administration routes
+	userRoutes := r.PathPrefix("/api/v1/users").Subrouter()
+	userRoutes.HandleFunc("/{id:[0-9]+}/sessions", RoleProtectedHandler(limited("users.revoke_sessions", handler.RevokeUserSessionsHandler), tracer, denylist, usecase.RoleAdmin).ServeHTTP).Methods(http.MethodDelete)
 
 	// Health check route
 	r.HandleFunc("/health", BasicHandler(handler.HealthCheckHandler, tracer).ServeHTTP).Methods(http.MethodGet)
 
+	// Probes are not traced, since they are polled every few seconds.
+	healthHandler := NewHealthHandler(registry)
+	r.HandleFunc("/livez", healthHandler.LivezHandler).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", healthHandler.ReadyzHandler).Methods(http.MethodGet)
+
+	if metricsHandler != nil {
+		r.Handle("/metrics", metricsHandler).Methods(http.MethodGet)
+	}
+
 	return r
 }
@@ -1,11 +1,15 @@
 package http
 
 import (
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/health"
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -21,7 +25,7 @@ func TestInitAPP(t *testing.T) {
 
 	tracer := trace.NewNoopTracerProvider().Tracer("test")
 
-	handler := InitAPP(cfg, tracer)
+	handler := InitAPP(cfg, tracer, slog.New(slog.NewJSONHandler(io.Discard, nil)), nil, health.NewRegistry(time.Second))
 
 	ts := httptest.NewServer(handler)
 	defer ts.Close()
@@ -1 +1,24 @@
 package cache
+
+import (
+	"context"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+// BookCache caches single books by ID and filtered book listings.
+// Getters return a nil value and no error on a cache miss.
+type BookCache interface {
+	GetBook(ctx context.Context, bookID int64) (*repository.Book, error)
+	SetBook(ctx context.Context, book *repository.Book) error
+	DeleteBook(ctx context.Context, bookID int64) error
+	GetFiltered(ctx context.Context, filter repository.BookFilter) (*BookList, error)
+	SetFiltered(ctx context.Context, filter repository.BookFilter, list *BookList) error
+	InvalidateFiltered(ctx context.Context) error
+}
+
+// BookList is a cached page of filtered books together with the total match count.
+type BookList struct {
+	Books []repository.Book `json:"books"`
+	Total int               `json:"total"`
+}
@@ -1 +1,15 @@
 package cache
+
+import (
+	"context"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+// CustomerCache caches customer accounts by ID.
+// GetCustomer returns a nil user and no error on a cache miss.
+type CustomerCache interface {
+	GetCustomer(ctx context.Context, userID int64) (*repository.User, error)
+	SetCustomer(ctx context.Context, user *repository.User) error
+	DeleteCustomer(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// LoginAttempts is the failed-login state recorded for a key.
+type LoginAttempts struct {
+	Failures    int
+	LastFailure time.Time
+}
+
+// LoginAttemptStore counts consecutive failed logins per key, such as an account email or a
+// client IP. A key is forgotten once no failure has been recorded for the retention period.
+// Get returns zero LoginAttempts and no error for an unknown key.
+type LoginAttemptStore interface {
+	Get(ctx context.Context, key string) (LoginAttempts, error)
+	RecordFailure(ctx context.Context, key string, retention time.Duration) (LoginAttempts, error)
+	Reset(ctx context.Context, key string) error
+}
@@ -1 +1,15 @@
 package cache
+
+import (
+	"context"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+// OrderCache caches orders by ID.
+// GetOrder returns a nil order and no error on a cache miss.
+type OrderCache interface {
+	GetOrder(ctx context.Context, orderID int64) (*repository.Order, error)
+	SetOrder(ctx context.Context, order *repository.Order) error
+	DeleteOrder(ctx context.Context, orderID int64) error
+}
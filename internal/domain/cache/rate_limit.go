@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStore keeps the token buckets used for rate limiting. Take removes a token from the
+// bucket for key, which holds up to burst tokens and refills at rate tokens per second.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (RateLimitResult, error)
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	ResetAfter time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token, set when the request is not allowed
+}
+
+// NewRateLimitResult describes a bucket that holds tokens after a request was allowed or denied.
+func NewRateLimitResult(allowed bool, tokens, rate float64, burst int) RateLimitResult {
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Limit:      burst,
+		Remaining:  int(tokens),
+		ResetAfter: secondsToDuration((float64(burst) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
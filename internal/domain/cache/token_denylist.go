@@ -0,0 +1,12 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// TokenDenylist records revoked access token IDs (the jti claim) until the tokens expire.
+type TokenDenylist interface {
+	Deny(ctx context.Context, tokenID string, expiresAt time.Time) error
+	IsDenied(ctx context.Context, tokenID string) (bool, error)
+}
@@ -5,8 +5,38 @@ import "net/http"
 type HTTPHandler interface {
 	RegisterHandler(w http.ResponseWriter, r *http.Request)
 	LoginHandler(w http.ResponseWriter, r *http.Request)
+	RefreshHandler(w http.ResponseWriter, r *http.Request)
+	LogoutHandler(w http.ResponseWriter, r *http.Request)
+	RevokeUserSessionsHandler(w http.ResponseWriter, r *http.Request)
+	ForgotPasswordHandler(w http.ResponseWriter, r *http.Request)
+	ResetPasswordHandler(w http.ResponseWriter, r *http.Request)
+	VerifyEmailHandler(w http.ResponseWriter, r *http.Request)
 	ListBooksHandler(w http.ResponseWriter, r *http.Request)
+	GetBookHandler(w http.ResponseWriter, r *http.Request)
+	CreateBookHandler(w http.ResponseWriter, r *http.Request)
+	UpdateBookHandler(w http.ResponseWriter, r *http.Request)
+	DeleteBookHandler(w http.ResponseWriter, r *http.Request)
+	RestockBookHandler(w http.ResponseWriter, r *http.Request)
+	ListStockHandler(w http.ResponseWriter, r *http.Request)
+	ListAuthorBooksHandler(w http.ResponseWriter, r *http.Request)
+	ListPublisherBooksHandler(w http.ResponseWriter, r *http.Request)
+	SetBookAuthorsHandler(w http.ResponseWriter, r *http.Request)
 	GetOrdersHandler(w http.ResponseWriter, r *http.Request)
+	GetOrderHandler(w http.ResponseWriter, r *http.Request)
 	CreateOrderHandler(w http.ResponseWriter, r *http.Request)
+	UpdateOrderStatusHandler(w http.ResponseWriter, r *http.Request)
+	CancelOrderHandler(w http.ResponseWriter, r *http.Request)
+	GetCartHandler(w http.ResponseWriter, r *http.Request)
+	AddCartItemHandler(w http.ResponseWriter, r *http.Request)
+	UpdateCartItemHandler(w http.ResponseWriter, r *http.Request)
+	RemoveCartItemHandler(w http.ResponseWriter, r *http.Request)
+	ClearCartHandler(w http.ResponseWriter, r *http.Request)
+	CheckoutCartHandler(w http.ResponseWriter, r *http.Request)
+	ListCategoriesHandler(w http.ResponseWriter, r *http.Request)
+	GetCategoryHandler(w http.ResponseWriter, r *http.Request)
+	CreateCategoryHandler(w http.ResponseWriter, r *http.Request)
+	UpdateCategoryHandler(w http.ResponseWriter, r *http.Request)
+	DeleteCategoryHandler(w http.ResponseWriter, r *http.Request)
+	SetBookCategoriesHandler(w http.ResponseWriter, r *http.Request)
 	HealthCheckHandler(w http.ResponseWriter, r *http.Request)
 }
@@ -0,0 +1,37 @@
+package health
+
+import "context"
+
+// HealthChecker reports whether a dependency is usable. Check must return once ctx is done.
+type HealthChecker interface {
+	Check(ctx context.Context) error
+}
+
+// HealthCheckerFunc adapts a function to the HealthChecker interface.
+type HealthCheckerFunc func(ctx context.Context) error
+
+// Check calls f(ctx).
+func (f HealthCheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+const (
+	StatusOK           = "ok"
+	StatusDown         = "down"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Report is the readiness of the service and each of its dependencies.
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentStatus `json:"components,omitempty"`
+}
+
+// ComponentStatus is the result of checking a single dependency. Optional components are
+// reported but do not make the service unready.
+type ComponentStatus struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Optional  bool    `json:"optional,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrAuthorNotFound is returned when a book is credited to an author that does not exist.
+var ErrAuthorNotFound = errors.New("author not found")
+
+// Roles a contributor can have on a book.
+const (
+	RoleAuthor     = "author"
+	RoleTranslator = "translator"
+	RoleEditor     = "editor"
+)
+
+type AuthorRepository interface {
+	GetAuthorByID(ctx context.Context, authorID int64) (*Author, error)
+	GetOrCreateAuthor(ctx context.Context, name string) (*Author, error)
+	SetBookAuthors(ctx context.Context, bookID int64, contributors []BookAuthor) error
+	GetBookAuthors(ctx context.Context, bookIDs []int64) ([]BookAuthor, error)
+}
+
+// Author is a person credited on books. Names that differ only in case, spacing or
+// punctuation belong to the same author.
+type Author struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// BookAuthor credits an author on a book in the given role. Position orders a book's
+// contributors, starting at 1.
+type BookAuthor struct {
+	BookID   int64
+	AuthorID int64
+	Name     string
+	Role     string
+	Position int
+}
@@ -2,13 +2,27 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrInsufficientStock is returned when a stock adjustment would make a book's stock negative.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// ErrBookNotFound is returned when a stock adjustment targets a book that does not exist.
+var ErrBookNotFound = errors.New("book not found")
+
 type BookRepository interface {
 	CreateBook(ctx context.Context, book *Book) (int64, error)
 	GetBookByID(ctx context.Context, bookID int64) (*Book, error)
+	UpdateBook(ctx context.Context, book *Book) error
+	DeleteBook(ctx context.Context, bookID int64) error
 	GetFiltered(ctx context.Context, filter BookFilter) ([]Book, int, error)
+	BookSearch(ctx context.Context, filter BookSearchFilter) ([]BookSearchResult, int, error)
+	GetBooksByIDs(ctx context.Context, bookIDs []int64) ([]Book, error)
+	GetBooksForUpdate(ctx context.Context, bookIDs []int64) ([]Book, error)
+	UpdateStock(ctx context.Context, bookID int64, delta int) error
+	GetStockLevels(ctx context.Context, filter StockFilter) ([]Book, int, error)
 }
 
 type Book struct {
@@ -16,17 +30,78 @@ type Book struct {
 	Title     string
 	Author    string
 	Price     float64
+	Stock     int
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
 type BookFilter struct {
-	Title     string
+	Title string
+	// Author matches part of the credit line or of the name of any author credited on the book.
 	Author    string
 	MinPrice  float64
 	MaxPrice  float64
 	StartDate time.Time
 	EndDate   time.Time
-	Limit     int
-	Offset    int
+	// Category is the slug of a category. Books in any of its descendants match as well.
+	Category string
+	// AuthorID matches books the author is credited on in any role.
+	AuthorID    int64
+	PublisherID int64
+	// Sort is a sort spec over BookSortFields such as "price,-created_at"; empty sorts by ID.
+	Sort string
+	// Cursor continues the listing after the last book of a previous page. Offset is ignored
+	// when it is set.
+	Cursor string
+	Limit  int
+	Offset int
+}
+
+// BookSortFields are the fields book listings can be sorted by.
+var BookSortFields = []string{"id", "title", "author", "price", "created_at"}
+
+// NewBookCursor returns the cursor for the page that follows book in a listing sorted by sort.
+func NewBookCursor(sort string, book Book) string {
+	fields, err := ParseSort(sort, BookSortFields)
+	if err != nil {
+		return ""
+	}
+
+	values := make([]interface{}, 0, len(fields)+1)
+	for _, field := range fields {
+		switch field.Name {
+		case "id":
+			values = append(values, book.ID)
+		case "title":
+			values = append(values, book.Title)
+		case "author":
+			values = append(values, book.Author)
+		case "price":
+			values = append(values, book.Price)
+		case "created_at":
+			values = append(values, book.CreatedAt)
+		}
+	}
+	return EncodeCursor(sort, append(values, book.ID)...)
+}
+
+// BookSearchFilter runs a full-text query, narrowed by the regular listing filters.
+type BookSearchFilter struct {
+	BookFilter
+	Query string
+}
+
+// BookSearchResult is a book matched by a full-text query. The highlights hold the title and
+// author with the matched terms wrapped in <mark> tags.
+type BookSearchResult struct {
+	Book
+	Rank            float64
+	TitleHighlight  string
+	AuthorHighlight string
+}
+
+type StockFilter struct {
+	MaxStock *int
+	Limit    int
+	Offset   int
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCartItemNotFound is returned when a cart does not contain the requested book.
+var ErrCartItemNotFound = errors.New("cart item not found")
+
+type CartRepository interface {
+	GetOrCreateCart(ctx context.Context, userID int64) (int64, error)
+	GetCartItems(ctx context.Context, cartID int64) ([]*CartItem, error)
+	GetCartItemsForUpdate(ctx context.Context, cartID int64) ([]*CartItem, error)
+	AddCartItem(ctx context.Context, cartID, bookID int64, quantity int) error
+	UpdateCartItem(ctx context.Context, cartID, bookID int64, quantity int) error
+	RemoveCartItem(ctx context.Context, cartID, bookID int64) error
+	ClearCart(ctx context.Context, cartID int64) error
+}
+
+// CartItem is a cart line joined with the current title, price and stock of its book.
+type CartItem struct {
+	ID       int64
+	CartID   int64
+	BookID   int64
+	Title    string
+	Price    float64
+	Stock    int
+	Quantity int
+	// BookDeleted is set when the book has been removed from the catalog since it was added to
+	// the cart. Title, Price and Stock are then zero.
+	BookDeleted bool
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrCategorySlugTaken is returned when another category already uses the slug.
+	ErrCategorySlugTaken = errors.New("category slug already exists")
+	// ErrCategoryHasChildren is returned when deleting a category that still has subcategories.
+	ErrCategoryHasChildren = errors.New("category has subcategories")
+	// ErrCategoryNotFound is returned when a book is assigned to a category that does not exist.
+	ErrCategoryNotFound = errors.New("category not found")
+)
+
+type CategoryRepository interface {
+	CreateCategory(ctx context.Context, category *Category) (int64, error)
+	GetCategoryByID(ctx context.Context, categoryID int64) (*Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*Category, error)
+	GetCategories(ctx context.Context) ([]Category, error)
+	UpdateCategory(ctx context.Context, category *Category) error
+	DeleteCategory(ctx context.Context, categoryID int64) error
+	SetBookCategories(ctx context.Context, bookID int64, categoryIDs []int64) error
+	GetBookCategories(ctx context.Context, bookID int64) ([]Category, error)
+	CountBooksByCategory(ctx context.Context, filter BookFilter) ([]CategoryCount, error)
+}
+
+// Category is a node of the category tree. ParentID is nil for top-level categories.
+type Category struct {
+	ID        int64
+	ParentID  *int64
+	Name      string
+	Slug      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CategoryCount is the number of books in a category, including the books of its descendants.
+type CategoryCount struct {
+	Slug  string
+	Count int
+}
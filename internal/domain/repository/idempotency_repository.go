@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, userID int64, key, requestHash string) (bool, error)
+	Get(ctx context.Context, userID int64, key string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, userID int64, key string) error
+}
+
+// IdempotencyRecord is a stored Idempotency-Key. StatusCode is zero while the original
+// request is still being processed.
+type IdempotencyRecord struct {
+	UserID       int64
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
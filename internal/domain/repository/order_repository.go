@@ -2,31 +2,99 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 )
 
+// ErrOrderStatusConflict is returned when an order's status changed before the update could be applied.
+var ErrOrderStatusConflict = errors.New("order status changed concurrently")
+
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, order *Order) (int64, error)
 	GetOrderByID(ctx context.Context, orderID int64) (*Order, error)
-	GetOrdersByUserID(ctx context.Context, userID int64, limit, offset int) ([]*Order, error)
+	GetOrdersByUserID(ctx context.Context, userID int64, filter OrderFilter) ([]*Order, error)
+	UpdateOrderStatus(ctx context.Context, orderID int64, fromStatus, toStatus string) error
+}
+
+type OrderStatusHistoryRepository interface {
+	CreateStatusHistory(ctx context.Context, history *OrderStatusHistory) (int64, error)
+	GetStatusHistoryByOrderID(ctx context.Context, orderID int64) ([]*OrderStatusHistory, error)
 }
 
 type OrderItemRepository interface {
 	CreateOrderItem(ctx context.Context, orderItem *OrderItem) (int64, error)
 	GetOrderItemsByOrderID(ctx context.Context, orderID int64) ([]*OrderItem, error)
+	GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []int64) (map[int64][]*OrderItem, error)
 }
 
 type Order struct {
 	ID        int64     `json:"id"`
 	UserID    int64     `json:"user_id"`
 	Status    string    `json:"status"`
+	Subtotal  float64   `json:"subtotal"`
+	Total     float64   `json:"total"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// OrderFilter pages through a user's orders.
+type OrderFilter struct {
+	// Sort is a sort spec over OrderSortFields; empty sorts newest first.
+	Sort string
+	// Cursor continues the listing after the last order of a previous page. Offset is ignored
+	// when it is set.
+	Cursor string
+	Limit  int
+	Offset int
+}
+
+// OrderSortFields are the fields order listings can be sorted by.
+var OrderSortFields = []string{"id", "status", "total", "created_at"}
+
+// DefaultOrderSort lists the newest orders first.
+const DefaultOrderSort = "-created_at,-id"
+
+// NewOrderCursor returns the cursor for the page that follows order in a listing sorted by sort.
+func NewOrderCursor(sort string, order *Order) string {
+	if sort == "" {
+		sort = DefaultOrderSort
+	}
+	fields, err := ParseSort(sort, OrderSortFields)
+	if err != nil {
+		return ""
+	}
+
+	values := make([]interface{}, 0, len(fields)+1)
+	for _, field := range fields {
+		switch field.Name {
+		case "id":
+			values = append(values, order.ID)
+		case "status":
+			values = append(values, order.Status)
+		case "total":
+			values = append(values, order.Total)
+		case "created_at":
+			values = append(values, order.CreatedAt)
+		}
+	}
+	return EncodeCursor(sort, append(values, order.ID)...)
+}
+
 type OrderItem struct {
-	ID       int64 `json:"id"`
-	OrderID  int64 `json:"order_id"`
-	BookID   int64 `json:"book_id"`
-	Quantity int   `json:"quantity"`
+	ID        int64   `json:"id"`
+	OrderID   int64   `json:"order_id"`
+	BookID    int64   `json:"book_id"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+}
+
+type OrderStatusHistory struct {
+	ID         int64     `json:"id"`
+	OrderID    int64     `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedBy  int64     `json:"changed_by"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
 }
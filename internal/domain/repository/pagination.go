@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrInvalidSort is returned when a sort spec names a field that cannot be sorted on.
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor is returned when a cursor is malformed or was issued for another sort.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// SortField orders a listing by one field, descending when Desc is set.
+type SortField struct {
+	Name string
+	Desc bool
+}
+
+// ParseSort parses a comma-separated sort spec such as "price,-created_at,title", where a
+// leading "-" sorts descending. Every field must be one of allowed.
+func ParseSort(spec string, allowed []string) ([]SortField, error) {
+	var fields []SortField
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		field := SortField{Name: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if !slices.Contains(allowed, field.Name) {
+			return nil, fmt.Errorf("%w: unknown field %q, must be one of %s", ErrInvalidSort, field.Name, strings.Join(allowed, ", "))
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("%w: field %q is repeated", ErrInvalidSort, field.Name)
+		}
+		seen[field.Name] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// cursor is the decoded form of an opaque pagination cursor: the sort it was issued for and the
+// sort values of the last row on the page, ending with its ID.
+type cursor struct {
+	Sort   string        `json:"s"`
+	Values []interface{} `json:"v"`
+}
+
+// EncodeCursor returns an opaque cursor that continues a listing sorted by sort after the row
+// with the given sort values.
+func EncodeCursor(sort string, values ...interface{}) string {
+	data, _ := json.Marshal(cursor{Sort: sort, Values: values})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor returns the sort values stored in a cursor issued for sort, in their text form.
+func DecodeCursor(value, sort string) ([]string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var c cursor
+	if err := decoder.Decode(&c); err != nil || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]string, len(c.Values))
+	for i, v := range c.Values {
+		switch v := v.(type) {
+		case string:
+			values[i] = v
+		case json.Number:
+			values[i] = v.String()
+		default:
+			return nil, ErrInvalidCursor
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrPublisherNotFound is returned when a book is assigned to a publisher that does not exist.
+var ErrPublisherNotFound = errors.New("publisher not found")
+
+type PublisherRepository interface {
+	GetPublisherByID(ctx context.Context, publisherID int64) (*Publisher, error)
+	GetOrCreatePublisher(ctx context.Context, name string) (*Publisher, error)
+	SetBookPublisher(ctx context.Context, bookID int64, publisherID *int64) error
+	GetBookPublisher(ctx context.Context, bookID int64) (*Publisher, error)
+}
+
+// Publisher publishes books. Names that differ only in case, spacing or punctuation belong to
+// the same publisher.
+type Publisher struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
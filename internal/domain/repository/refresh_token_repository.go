@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrRefreshTokenRotated is returned when a refresh token has already been exchanged.
+var ErrRefreshTokenRotated = errors.New("refresh token already rotated")
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *RefreshToken) (int64, error)
+	GetByHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	Rotate(ctx context.Context, id int64) error
+	RevokeFamily(ctx context.Context, familyID string) ([]*RefreshToken, error)
+	RevokeByUserID(ctx context.Context, userID int64) ([]*RefreshToken, error)
+}
+
+// RefreshToken is a stored refresh token. Only the hash of the token is kept. AccessTokenID and
+// AccessExpiresAt identify the access token issued alongside it, so it can be denied on revocation.
+type RefreshToken struct {
+	ID              int64
+	UserID          int64
+	TokenHash       string
+	FamilyID        string
+	AccessTokenID   string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	RotatedAt       *time.Time
+	RevokedAt       *time.Time
+	CreatedAt       time.Time
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/masatrio/bookstore-api/utils"
 )
@@ -10,8 +11,15 @@ type Repository interface {
 	BookRepository() BookRepository
 	OrderRepository() OrderRepository
 	OrderItemRepository() OrderItemRepository
+	OrderStatusHistoryRepository() OrderStatusHistoryRepository
 	UserRepository() UserRepository
-	WithTransaction(TransactionFunc) utils.CustomError
+	CartRepository() CartRepository
+	CategoryRepository() CategoryRepository
+	AuthorRepository() AuthorRepository
+	PublisherRepository() PublisherRepository
+	RefreshTokenRepository() RefreshTokenRepository
+	UserTokenRepository() UserTokenRepository
+	WithTransaction(context.Context, *sql.TxOptions, TransactionFunc) utils.CustomError
 }
 
 type TransactionFunc func(ctx context.Context) utils.CustomError
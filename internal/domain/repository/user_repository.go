@@ -9,13 +9,18 @@ type UserRepository interface {
 	Create(ctx context.Context, user *User) (int64, error)
 	GetByID(ctx context.Context, id int64) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
+	UpdateRole(ctx context.Context, id int64, role string) error
+	UpdatePassword(ctx context.Context, id int64, password string) error
+	MarkEmailVerified(ctx context.Context, id int64) error
 }
 
 type User struct {
-	ID        int64
-	Name      string
-	Email     string
-	Password  string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              int64
+	Name            string
+	Email           string
+	Password        string
+	Role            string
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUserTokenUsed is returned when a single-use token has already been consumed.
+var ErrUserTokenUsed = errors.New("user token already used")
+
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *UserToken) (int64, error)
+	GetByHash(ctx context.Context, purpose, tokenHash string) (*UserToken, error)
+	MarkUsed(ctx context.Context, id int64) error
+	InvalidateByUserID(ctx context.Context, userID int64, purpose string) error
+}
+
+// UserToken is a single-use token emailed to a user, such as a password reset token.
+// Only the hash of the token is stored.
+type UserToken struct {
+	ID        int64
+	UserID    int64
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
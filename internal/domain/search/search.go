@@ -0,0 +1,79 @@
+package search
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+// Facet names understood by SearchIndex implementations.
+const (
+	FacetAuthor = "author"
+	FacetPrice  = "price"
+)
+
+// Document is the searchable view of a book.
+type Document struct {
+	ID        int64
+	Title     string
+	Author    string
+	Price     float64
+	CreatedAt time.Time
+}
+
+// NewDocument returns the searchable view of book.
+func NewDocument(book repository.Book) Document {
+	return Document{
+		ID:        book.ID,
+		Title:     book.Title,
+		Author:    book.Author,
+		Price:     book.Price,
+		CreatedAt: book.CreatedAt,
+	}
+}
+
+// Query is a full-text query narrowed by the regular listing filters. Title and Author match
+// by case-insensitive substring. Facets lists the facets to count over every match.
+type Query struct {
+	Text      string
+	Title     string
+	Author    string
+	MinPrice  float64
+	MaxPrice  float64
+	StartDate time.Time
+	EndDate   time.Time
+	Facets    []string
+	Limit     int
+	Offset    int
+}
+
+// Hit is a matching document ID, most relevant first. The highlights hold the title and author
+// with the matched terms wrapped in <mark> tags.
+type Hit struct {
+	ID              int64
+	Score           float64
+	TitleHighlight  string
+	AuthorHighlight string
+}
+
+// FacetCount is the number of matches that share a facet value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// Result is a page of hits together with the total match count and the requested facets.
+type Result struct {
+	Hits   []Hit
+	Total  int
+	Facets map[string][]FacetCount
+}
+
+// SearchIndex indexes books and answers full-text queries. The database stays the source of
+// truth: an index can always be rebuilt from it.
+type SearchIndex interface {
+	Index(ctx context.Context, doc Document) error
+	Delete(ctx context.Context, id int64) error
+	Query(ctx context.Context, query Query) (*Result, error)
+}
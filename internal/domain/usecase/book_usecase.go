@@ -12,19 +12,89 @@ type Book struct {
 	Title     string    `json:"title"`
 	Author    string    `json:"author"`
 	Price     float64   `json:"price"`
+	Stock     int       `json:"stock"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Authors credits the people behind Author with their roles. It is set when a single book
+	// is retrieved and on author and publisher listings.
+	Authors []BookAuthor `json:"authors,omitempty"`
+	// Publisher is only set when a single book is retrieved. When creating or updating a book,
+	// it is matched by name and created if needed; an empty name removes it.
+	Publisher *Publisher `json:"publisher,omitempty"`
+	// Categories is only set when a single book is retrieved.
+	Categories []Category `json:"categories,omitempty"`
+	// Highlight is only set on search results.
+	Highlight *BookHighlight `json:"highlight,omitempty"`
+}
+
+// BookHighlight holds the title and author with the matched search terms wrapped in <mark> tags.
+type BookHighlight struct {
+	Title  string `json:"title"`
+	Author string `json:"author"`
+}
+
+// Author is a person credited on books.
+type Author struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// BookAuthor credits an author on a book as its author, translator or editor.
+type BookAuthor struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type Publisher struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+type UpdateBookInput struct {
+	Title *string `json:"title,omitempty"`
+	// Author is the credit line. Setting it re-credits the book's authors, splitting it on "&"
+	// and ";"; translators and editors are kept.
+	Author    *string    `json:"author,omitempty"`
+	Price     *float64   `json:"price,omitempty"`
+	Publisher *Publisher `json:"publisher,omitempty"`
+}
+
+// SetBookAuthorsInput replaces a book's contributors, in credit order. The names of those with
+// the author role become the book's credit line.
+type SetBookAuthorsInput struct {
+	Authors []BookAuthorInput `json:"authors"`
+}
+
+// BookAuthorInput credits an author by name, creating the author if no name matches. Role
+// defaults to "author".
+type BookAuthorInput struct {
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
 }
 
 type ListBooksInput struct {
+	// Query is a full-text search over title and author. Results are ranked by relevance
+	// instead of listed in catalog order.
+	Query     string    `json:"q,omitempty"`
 	Title     string    `json:"title,omitempty"`
 	Author    string    `json:"author,omitempty"`
 	MinPrice  float64   `json:"min_price,omitempty"`
 	MaxPrice  float64   `json:"max_price,omitempty"`
 	StartDate time.Time `json:"start_date,omitempty"`
 	EndDate   time.Time `json:"end_date,omitempty"`
-	Limit     int       `json:"limit,omitempty"`
-	Offset    int       `json:"offset,omitempty"`
+	// Category is the slug of a category. Books in its subcategories are listed as well.
+	Category string `json:"category,omitempty"`
+	// AuthorID lists the books the author is credited on in any role.
+	AuthorID    int64 `json:"author_id,omitempty"`
+	PublisherID int64 `json:"publisher_id,omitempty"`
+	// Sort is a comma-separated list of fields such as "price,-created_at", where a leading
+	// "-" sorts descending. It cannot be combined with Query.
+	Sort string `json:"sort,omitempty"`
+	// Cursor is the NextCursor of a previous page. When set, Offset is ignored.
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
 }
 
 type ListBooksOutput struct {
@@ -32,10 +102,63 @@ type ListBooksOutput struct {
 	TotalCount int    `json:"total_count"`
 	Limit      int    `json:"limit"`
 	Offset     int    `json:"offset"`
+	// NextCursor continues the listing after this page. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Facets counts every match by facet value. Listings count books per category, including the
+	// books of subcategories; searches served by a search index count authors and prices.
+	Facets map[string][]FacetCount `json:"facets,omitempty"`
+}
+
+// AuthorBooksOutput lists the books an author is credited on.
+type AuthorBooksOutput struct {
+	Author Author `json:"author"`
+	ListBooksOutput
+}
+
+// PublisherBooksOutput lists the books of a publisher.
+type PublisherBooksOutput struct {
+	Publisher Publisher `json:"publisher"`
+	ListBooksOutput
+}
+
+// FacetCount is the number of matching books that share a facet value.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type RestockInput struct {
+	Quantity int `json:"quantity"`
+}
+
+type StockLevel struct {
+	BookID int64  `json:"book_id"`
+	Title  string `json:"title"`
+	Stock  int    `json:"stock"`
+}
+
+type ListStockInput struct {
+	MaxStock *int `json:"max_stock,omitempty"`
+	Limit    int  `json:"limit,omitempty"`
+	Offset   int  `json:"offset,omitempty"`
+}
+
+type ListStockOutput struct {
+	Items      []StockLevel `json:"items"`
+	TotalCount int          `json:"total_count"`
+	Limit      int          `json:"limit"`
+	Offset     int          `json:"offset"`
 }
 
 type BookUseCase interface {
 	CreateBook(ctx context.Context, input Book) (*Book, utils.CustomError)
 	GetBook(ctx context.Context, id int64) (*Book, utils.CustomError)
+	UpdateBook(ctx context.Context, id int64, input UpdateBookInput) (*Book, utils.CustomError)
+	DeleteBook(ctx context.Context, id int64) utils.CustomError
+	RestockBook(ctx context.Context, id int64, input RestockInput) (*Book, utils.CustomError)
+	ListStock(ctx context.Context, input ListStockInput) (*ListStockOutput, utils.CustomError)
 	ListBooks(ctx context.Context, input ListBooksInput) (*ListBooksOutput, utils.CustomError)
+	SetBookAuthors(ctx context.Context, id int64, input SetBookAuthorsInput) (*Book, utils.CustomError)
+	ListAuthorBooks(ctx context.Context, authorID int64, input ListBooksInput) (*AuthorBooksOutput, utils.CustomError)
+	ListPublisherBooks(ctx context.Context, publisherID int64, input ListBooksInput) (*PublisherBooksOutput, utils.CustomError)
 }
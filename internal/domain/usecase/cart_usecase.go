@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type AddCartItemInput struct {
+	BookID   int64 `json:"book_id"`
+	Quantity int   `json:"quantity"`
+}
+
+type UpdateCartItemInput struct {
+	Quantity int `json:"quantity"`
+}
+
+type CartItemDetail struct {
+	BookID    int64   `json:"book_id"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	LineTotal float64 `json:"line_total"`
+	Available int     `json:"available"`
+	// Unavailable is set when the book is no longer sold. The line must be removed before checkout.
+	Unavailable bool `json:"unavailable,omitempty"`
+}
+
+type CartOutput struct {
+	Items    []CartItemDetail `json:"items"`
+	Subtotal float64          `json:"subtotal"`
+}
+
+type CartUseCase interface {
+	GetCart(ctx context.Context, userID int64) (*CartOutput, utils.CustomError)
+	AddItem(ctx context.Context, userID int64, input AddCartItemInput) (*CartOutput, utils.CustomError)
+	UpdateItem(ctx context.Context, userID, bookID int64, input UpdateCartItemInput) (*CartOutput, utils.CustomError)
+	RemoveItem(ctx context.Context, userID, bookID int64) (*CartOutput, utils.CustomError)
+	ClearCart(ctx context.Context, userID int64) utils.CustomError
+	Checkout(ctx context.Context, userID int64) (*CreateOrderOutput, utils.CustomError)
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type Category struct {
+	ID        int64     `json:"id"`
+	ParentID  *int64    `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// Children is only set when categories are returned as a tree.
+	Children []Category `json:"children,omitempty"`
+}
+
+// CategoryInput creates or replaces a category. A nil ParentID makes it a top-level category,
+// and an empty Slug is derived from the name.
+type CategoryInput struct {
+	ParentID *int64 `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+}
+
+type SetBookCategoriesInput struct {
+	CategoryIDs []int64 `json:"category_ids"`
+}
+
+type CategoryUseCase interface {
+	ListCategories(ctx context.Context) ([]Category, utils.CustomError)
+	GetCategory(ctx context.Context, id int64) (*Category, utils.CustomError)
+	CreateCategory(ctx context.Context, input CategoryInput) (*Category, utils.CustomError)
+	UpdateCategory(ctx context.Context, id int64, input CategoryInput) (*Category, utils.CustomError)
+	DeleteCategory(ctx context.Context, id int64) utils.CustomError
+	SetBookCategories(ctx context.Context, bookID int64, input SetBookCategoriesInput) ([]Category, utils.CustomError)
+}
@@ -51,6 +51,20 @@ func (mr *MockBookUseCaseMockRecorder) CreateBook(ctx, input interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBook", reflect.TypeOf((*MockBookUseCase)(nil).CreateBook), ctx, input)
 }
 
+// DeleteBook mocks base method.
+func (m *MockBookUseCase) DeleteBook(ctx context.Context, id int64) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBook", ctx, id)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// DeleteBook indicates an expected call of DeleteBook.
+func (mr *MockBookUseCaseMockRecorder) DeleteBook(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBook", reflect.TypeOf((*MockBookUseCase)(nil).DeleteBook), ctx, id)
+}
+
 // GetBook mocks base method.
 func (m *MockBookUseCase) GetBook(ctx context.Context, id int64) (*usecase.Book, utils.CustomError) {
 	m.ctrl.T.Helper()
@@ -66,6 +80,21 @@ func (mr *MockBookUseCaseMockRecorder) GetBook(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookUseCase)(nil).GetBook), ctx, id)
 }
 
+// ListAuthorBooks mocks base method.
+func (m *MockBookUseCase) ListAuthorBooks(ctx context.Context, authorID int64, input usecase.ListBooksInput) (*usecase.AuthorBooksOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuthorBooks", ctx, authorID, input)
+	ret0, _ := ret[0].(*usecase.AuthorBooksOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// ListAuthorBooks indicates an expected call of ListAuthorBooks.
+func (mr *MockBookUseCaseMockRecorder) ListAuthorBooks(ctx, authorID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthorBooks", reflect.TypeOf((*MockBookUseCase)(nil).ListAuthorBooks), ctx, authorID, input)
+}
+
 // ListBooks mocks base method.
 func (m *MockBookUseCase) ListBooks(ctx context.Context, input usecase.ListBooksInput) (*usecase.ListBooksOutput, utils.CustomError) {
 	m.ctrl.T.Helper()
@@ -80,3 +109,78 @@ func (mr *MockBookUseCaseMockRecorder) ListBooks(ctx, input interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBooks", reflect.TypeOf((*MockBookUseCase)(nil).ListBooks), ctx, input)
 }
+
+// ListPublisherBooks mocks base method.
+func (m *MockBookUseCase) ListPublisherBooks(ctx context.Context, publisherID int64, input usecase.ListBooksInput) (*usecase.PublisherBooksOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPublisherBooks", ctx, publisherID, input)
+	ret0, _ := ret[0].(*usecase.PublisherBooksOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// ListPublisherBooks indicates an expected call of ListPublisherBooks.
+func (mr *MockBookUseCaseMockRecorder) ListPublisherBooks(ctx, publisherID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPublisherBooks", reflect.TypeOf((*MockBookUseCase)(nil).ListPublisherBooks), ctx, publisherID, input)
+}
+
+// ListStock mocks base method.
+func (m *MockBookUseCase) ListStock(ctx context.Context, input usecase.ListStockInput) (*usecase.ListStockOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStock", ctx, input)
+	ret0, _ := ret[0].(*usecase.ListStockOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// ListStock indicates an expected call of ListStock.
+func (mr *MockBookUseCaseMockRecorder) ListStock(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStock", reflect.TypeOf((*MockBookUseCase)(nil).ListStock), ctx, input)
+}
+
+// RestockBook mocks base method.
+func (m *MockBookUseCase) RestockBook(ctx context.Context, id int64, input usecase.RestockInput) (*usecase.Book, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestockBook", ctx, id, input)
+	ret0, _ := ret[0].(*usecase.Book)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// RestockBook indicates an expected call of RestockBook.
+func (mr *MockBookUseCaseMockRecorder) RestockBook(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestockBook", reflect.TypeOf((*MockBookUseCase)(nil).RestockBook), ctx, id, input)
+}
+
+// SetBookAuthors mocks base method.
+func (m *MockBookUseCase) SetBookAuthors(ctx context.Context, id int64, input usecase.SetBookAuthorsInput) (*usecase.Book, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookAuthors", ctx, id, input)
+	ret0, _ := ret[0].(*usecase.Book)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// SetBookAuthors indicates an expected call of SetBookAuthors.
+func (mr *MockBookUseCaseMockRecorder) SetBookAuthors(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookAuthors", reflect.TypeOf((*MockBookUseCase)(nil).SetBookAuthors), ctx, id, input)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookUseCase) UpdateBook(ctx context.Context, id int64, input usecase.UpdateBookInput) (*usecase.Book, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, id, input)
+	ret0, _ := ret[0].(*usecase.Book)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookUseCaseMockRecorder) UpdateBook(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookUseCase)(nil).UpdateBook), ctx, id, input)
+}
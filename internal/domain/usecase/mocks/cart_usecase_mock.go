@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/usecase/cart_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	usecase "github.com/masatrio/bookstore-api/internal/domain/usecase"
+	utils "github.com/masatrio/bookstore-api/utils"
+)
+
+// MockCartUseCase is a mock of CartUseCase interface.
+type MockCartUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartUseCaseMockRecorder
+}
+
+// MockCartUseCaseMockRecorder is the mock recorder for MockCartUseCase.
+type MockCartUseCaseMockRecorder struct {
+	mock *MockCartUseCase
+}
+
+// NewMockCartUseCase creates a new mock instance.
+func NewMockCartUseCase(ctrl *gomock.Controller) *MockCartUseCase {
+	mock := &MockCartUseCase{ctrl: ctrl}
+	mock.recorder = &MockCartUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartUseCase) EXPECT() *MockCartUseCaseMockRecorder {
+	return m.recorder
+}
+
+// AddItem mocks base method.
+func (m *MockCartUseCase) AddItem(ctx context.Context, userID int64, input usecase.AddCartItemInput) (*usecase.CartOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItem", ctx, userID, input)
+	ret0, _ := ret[0].(*usecase.CartOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// AddItem indicates an expected call of AddItem.
+func (mr *MockCartUseCaseMockRecorder) AddItem(ctx, userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItem", reflect.TypeOf((*MockCartUseCase)(nil).AddItem), ctx, userID, input)
+}
+
+// Checkout mocks base method.
+func (m *MockCartUseCase) Checkout(ctx context.Context, userID int64) (*usecase.CreateOrderOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Checkout", ctx, userID)
+	ret0, _ := ret[0].(*usecase.CreateOrderOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// Checkout indicates an expected call of Checkout.
+func (mr *MockCartUseCaseMockRecorder) Checkout(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Checkout", reflect.TypeOf((*MockCartUseCase)(nil).Checkout), ctx, userID)
+}
+
+// ClearCart mocks base method.
+func (m *MockCartUseCase) ClearCart(ctx context.Context, userID int64) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearCart", ctx, userID)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// ClearCart indicates an expected call of ClearCart.
+func (mr *MockCartUseCaseMockRecorder) ClearCart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearCart", reflect.TypeOf((*MockCartUseCase)(nil).ClearCart), ctx, userID)
+}
+
+// GetCart mocks base method.
+func (m *MockCartUseCase) GetCart(ctx context.Context, userID int64) (*usecase.CartOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCart", ctx, userID)
+	ret0, _ := ret[0].(*usecase.CartOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// GetCart indicates an expected call of GetCart.
+func (mr *MockCartUseCaseMockRecorder) GetCart(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCart", reflect.TypeOf((*MockCartUseCase)(nil).GetCart), ctx, userID)
+}
+
+// RemoveItem mocks base method.
+func (m *MockCartUseCase) RemoveItem(ctx context.Context, userID, bookID int64) (*usecase.CartOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveItem", ctx, userID, bookID)
+	ret0, _ := ret[0].(*usecase.CartOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// RemoveItem indicates an expected call of RemoveItem.
+func (mr *MockCartUseCaseMockRecorder) RemoveItem(ctx, userID, bookID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveItem", reflect.TypeOf((*MockCartUseCase)(nil).RemoveItem), ctx, userID, bookID)
+}
+
+// UpdateItem mocks base method.
+func (m *MockCartUseCase) UpdateItem(ctx context.Context, userID, bookID int64, input usecase.UpdateCartItemInput) (*usecase.CartOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItem", ctx, userID, bookID, input)
+	ret0, _ := ret[0].(*usecase.CartOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// UpdateItem indicates an expected call of UpdateItem.
+func (mr *MockCartUseCaseMockRecorder) UpdateItem(ctx, userID, bookID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockCartUseCase)(nil).UpdateItem), ctx, userID, bookID, input)
+}
@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/usecase/category_usecase.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	usecase "github.com/masatrio/bookstore-api/internal/domain/usecase"
+	utils "github.com/masatrio/bookstore-api/utils"
+)
+
+// MockCategoryUseCase is a mock of CategoryUseCase interface.
+type MockCategoryUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCategoryUseCaseMockRecorder
+}
+
+// MockCategoryUseCaseMockRecorder is the mock recorder for MockCategoryUseCase.
+type MockCategoryUseCaseMockRecorder struct {
+	mock *MockCategoryUseCase
+}
+
+// NewMockCategoryUseCase creates a new mock instance.
+func NewMockCategoryUseCase(ctrl *gomock.Controller) *MockCategoryUseCase {
+	mock := &MockCategoryUseCase{ctrl: ctrl}
+	mock.recorder = &MockCategoryUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCategoryUseCase) EXPECT() *MockCategoryUseCaseMockRecorder {
+	return m.recorder
+}
+
+// CreateCategory mocks base method.
+func (m *MockCategoryUseCase) CreateCategory(ctx context.Context, input usecase.CategoryInput) (*usecase.Category, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", ctx, input)
+	ret0, _ := ret[0].(*usecase.Category)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockCategoryUseCaseMockRecorder) CreateCategory(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockCategoryUseCase)(nil).CreateCategory), ctx, input)
+}
+
+// DeleteCategory mocks base method.
+func (m *MockCategoryUseCase) DeleteCategory(ctx context.Context, id int64) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, id)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// DeleteCategory indicates an expected call of DeleteCategory.
+func (mr *MockCategoryUseCaseMockRecorder) DeleteCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryUseCase)(nil).DeleteCategory), ctx, id)
+}
+
+// GetCategory mocks base method.
+func (m *MockCategoryUseCase) GetCategory(ctx context.Context, id int64) (*usecase.Category, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategory", ctx, id)
+	ret0, _ := ret[0].(*usecase.Category)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// GetCategory indicates an expected call of GetCategory.
+func (mr *MockCategoryUseCaseMockRecorder) GetCategory(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategory", reflect.TypeOf((*MockCategoryUseCase)(nil).GetCategory), ctx, id)
+}
+
+// ListCategories mocks base method.
+func (m *MockCategoryUseCase) ListCategories(ctx context.Context) ([]usecase.Category, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", ctx)
+	ret0, _ := ret[0].([]usecase.Category)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockCategoryUseCaseMockRecorder) ListCategories(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockCategoryUseCase)(nil).ListCategories), ctx)
+}
+
+// SetBookCategories mocks base method.
+func (m *MockCategoryUseCase) SetBookCategories(ctx context.Context, bookID int64, input usecase.SetBookCategoriesInput) ([]usecase.Category, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBookCategories", ctx, bookID, input)
+	ret0, _ := ret[0].([]usecase.Category)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// SetBookCategories indicates an expected call of SetBookCategories.
+func (mr *MockCategoryUseCaseMockRecorder) SetBookCategories(ctx, bookID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBookCategories", reflect.TypeOf((*MockCategoryUseCase)(nil).SetBookCategories), ctx, bookID, input)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockCategoryUseCase) UpdateCategory(ctx context.Context, id int64, input usecase.CategoryInput) (*usecase.Category, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, id, input)
+	ret0, _ := ret[0].(*usecase.Category)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockCategoryUseCaseMockRecorder) UpdateCategory(ctx, id, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCategoryUseCase)(nil).UpdateCategory), ctx, id, input)
+}
@@ -36,6 +36,21 @@ func (m *MockOrderUseCase) EXPECT() *MockOrderUseCaseMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderUseCase) CancelOrder(ctx context.Context, orderID, userID int64) (*usecase.OrderStatusOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderID, userID)
+	ret0, _ := ret[0].(*usecase.OrderStatusOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderUseCaseMockRecorder) CancelOrder(ctx, orderID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderUseCase)(nil).CancelOrder), ctx, orderID, userID)
+}
+
 // CreateOrder mocks base method.
 func (m *MockOrderUseCase) CreateOrder(ctx context.Context, input usecase.CreateOrderInput, userID int64) (*usecase.CreateOrderOutput, utils.CustomError) {
 	m.ctrl.T.Helper()
@@ -51,17 +66,47 @@ func (mr *MockOrderUseCaseMockRecorder) CreateOrder(ctx, input, userID interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderUseCase)(nil).CreateOrder), ctx, input, userID)
 }
 
+// GetOrder mocks base method.
+func (m *MockOrderUseCase) GetOrder(ctx context.Context, orderID, userID int64) (*usecase.GetOrderOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID, userID)
+	ret0, _ := ret[0].(*usecase.GetOrderOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderUseCaseMockRecorder) GetOrder(ctx, orderID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderUseCase)(nil).GetOrder), ctx, orderID, userID)
+}
+
 // GetOrders mocks base method.
-func (m *MockOrderUseCase) GetOrders(ctx context.Context, userID int64, limit, offset int) ([]usecase.GetOrderOutput, utils.CustomError) {
+func (m *MockOrderUseCase) GetOrders(ctx context.Context, userID int64, input usecase.GetOrdersInput) (*usecase.GetOrdersOutput, utils.CustomError) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrders", ctx, userID, limit, offset)
-	ret0, _ := ret[0].([]usecase.GetOrderOutput)
+	ret := m.ctrl.Call(m, "GetOrders", ctx, userID, input)
+	ret0, _ := ret[0].(*usecase.GetOrdersOutput)
 	ret1, _ := ret[1].(utils.CustomError)
 	return ret0, ret1
 }
 
 // GetOrders indicates an expected call of GetOrders.
-func (mr *MockOrderUseCaseMockRecorder) GetOrders(ctx, userID, limit, offset interface{}) *gomock.Call {
+func (mr *MockOrderUseCaseMockRecorder) GetOrders(ctx, userID, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderUseCase)(nil).GetOrders), ctx, userID, input)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockOrderUseCase) UpdateOrderStatus(ctx context.Context, orderID int64, input usecase.UpdateOrderStatusInput, actorID int64) (*usecase.OrderStatusOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", ctx, orderID, input, actorID)
+	ret0, _ := ret[0].(*usecase.OrderStatusOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockOrderUseCaseMockRecorder) UpdateOrderStatus(ctx, orderID, input, actorID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrderUseCase)(nil).GetOrders), ctx, userID, limit, offset)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockOrderUseCase)(nil).UpdateOrderStatus), ctx, orderID, input, actorID)
 }
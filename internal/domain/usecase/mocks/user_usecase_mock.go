@@ -36,6 +36,20 @@ func (m *MockUserUseCase) EXPECT() *MockUserUseCaseMockRecorder {
 	return m.recorder
 }
 
+// ForgotPassword mocks base method.
+func (m *MockUserUseCase) ForgotPassword(ctx context.Context, input usecase.ForgotPasswordInput) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", ctx, input)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockUserUseCaseMockRecorder) ForgotPassword(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockUserUseCase)(nil).ForgotPassword), ctx, input)
+}
+
 // Login mocks base method.
 func (m *MockUserUseCase) Login(ctx context.Context, input usecase.LoginInput) (*usecase.LoginOutput, utils.CustomError) {
 	m.ctrl.T.Helper()
@@ -51,6 +65,35 @@ func (mr *MockUserUseCaseMockRecorder) Login(ctx, input interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUseCase)(nil).Login), ctx, input)
 }
 
+// Logout mocks base method.
+func (m *MockUserUseCase) Logout(ctx context.Context, input usecase.LogoutInput, accessToken usecase.AccessToken) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, input, accessToken)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserUseCaseMockRecorder) Logout(ctx, input, accessToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserUseCase)(nil).Logout), ctx, input, accessToken)
+}
+
+// Refresh mocks base method.
+func (m *MockUserUseCase) Refresh(ctx context.Context, input usecase.RefreshInput) (*usecase.RefreshOutput, utils.CustomError) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, input)
+	ret0, _ := ret[0].(*usecase.RefreshOutput)
+	ret1, _ := ret[1].(utils.CustomError)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserUseCaseMockRecorder) Refresh(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserUseCase)(nil).Refresh), ctx, input)
+}
+
 // Register mocks base method.
 func (m *MockUserUseCase) Register(ctx context.Context, input usecase.RegisterInput) (*usecase.RegisterOutput, utils.CustomError) {
 	m.ctrl.T.Helper()
@@ -65,3 +108,45 @@ func (mr *MockUserUseCaseMockRecorder) Register(ctx, input interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUseCase)(nil).Register), ctx, input)
 }
+
+// ResetPassword mocks base method.
+func (m *MockUserUseCase) ResetPassword(ctx context.Context, input usecase.ResetPasswordInput) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, input)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserUseCaseMockRecorder) ResetPassword(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserUseCase)(nil).ResetPassword), ctx, input)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockUserUseCase) RevokeSessions(ctx context.Context, userID int64) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", ctx, userID)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockUserUseCaseMockRecorder) RevokeSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockUserUseCase)(nil).RevokeSessions), ctx, userID)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserUseCase) VerifyEmail(ctx context.Context, input usecase.VerifyEmailInput) utils.CustomError {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, input)
+	ret0, _ := ret[0].(utils.CustomError)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserUseCaseMockRecorder) VerifyEmail(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserUseCase)(nil).VerifyEmail), ctx, input)
+}
@@ -6,30 +6,94 @@ import (
 	"github.com/masatrio/bookstore-api/utils"
 )
 
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
+)
+
 type OrderItem struct {
 	BookID   int64 `json:"book_id"`
 	Quantity int   `json:"quantity"`
 }
 
+// OrderItemDetail is an order line as it was priced at purchase time.
+type OrderItemDetail struct {
+	BookID    int64   `json:"book_id"`
+	Title     string  `json:"title"`
+	Quantity  int     `json:"quantity"`
+	UnitPrice float64 `json:"unit_price"`
+	LineTotal float64 `json:"line_total"`
+}
+
 type CreateOrderInput struct {
 	Items []OrderItem `json:"items"`
+	// CartID, when set, checks out the cart instead: its items are read inside the order
+	// transaction and the cart is emptied once the order has been placed.
+	CartID int64 `json:"-"`
 }
 
 type CreateOrderOutput struct {
-	OrderID   int64       `json:"order_id"`
-	Items     []OrderItem `json:"items"`
-	Status    string      `json:"status"`
-	CreatedAt string      `json:"created_at"`
+	OrderID   int64             `json:"order_id"`
+	Items     []OrderItemDetail `json:"items"`
+	Status    string            `json:"status"`
+	Subtotal  float64           `json:"subtotal"`
+	Total     float64           `json:"total"`
+	CreatedAt string            `json:"created_at"`
 }
 
 type GetOrderOutput struct {
-	OrderID   int64       `json:"order_id"`
-	Items     []OrderItem `json:"items"`
-	Status    string      `json:"status"`
-	CreatedAt string      `json:"created_at"`
+	OrderID   int64               `json:"order_id"`
+	Items     []OrderItemDetail   `json:"items"`
+	Status    string              `json:"status"`
+	Subtotal  float64             `json:"subtotal"`
+	Total     float64             `json:"total"`
+	History   []OrderStatusChange `json:"history,omitempty"`
+	CreatedAt string              `json:"created_at"`
+}
+
+type UpdateOrderStatusInput struct {
+	Status string `json:"status"`
+	Note   string `json:"note,omitempty"`
+}
+
+type GetOrdersInput struct {
+	// Sort is a comma-separated list of fields such as "-total", where a leading "-" sorts
+	// descending. Orders are listed newest first by default.
+	Sort string `json:"sort,omitempty"`
+	// Cursor is the NextCursor of a previous page. When set, Offset is ignored.
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+}
+
+type GetOrdersOutput struct {
+	Orders []GetOrderOutput `json:"orders"`
+	// NextCursor continues the listing after this page. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+type OrderStatusChange struct {
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status"`
+	ChangedBy  int64  `json:"changed_by,omitempty"`
+	Note       string `json:"note,omitempty"`
+	ChangedAt  string `json:"changed_at"`
+}
+
+type OrderStatusOutput struct {
+	OrderID int64               `json:"order_id"`
+	Status  string              `json:"status"`
+	History []OrderStatusChange `json:"history"`
 }
 
 type OrderUseCase interface {
 	CreateOrder(ctx context.Context, input CreateOrderInput, userID int64) (*CreateOrderOutput, utils.CustomError)
-	GetOrders(ctx context.Context, userID int64, limit, offset int) ([]GetOrderOutput, utils.CustomError)
+	GetOrders(ctx context.Context, userID int64, input GetOrdersInput) (*GetOrdersOutput, utils.CustomError)
+	GetOrder(ctx context.Context, orderID, userID int64) (*GetOrderOutput, utils.CustomError)
+	UpdateOrderStatus(ctx context.Context, orderID int64, input UpdateOrderStatusInput, actorID int64) (*OrderStatusOutput, utils.CustomError)
+	CancelOrder(ctx context.Context, orderID, userID int64) (*OrderStatusOutput, utils.CustomError)
 }
@@ -2,14 +2,22 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/masatrio/bookstore-api/utils"
 )
 
+const (
+	RoleCustomer = "customer"
+	RoleAdmin    = "admin"
+)
+
 type User struct {
-	ID    int64  `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	EmailVerified bool   `json:"email_verified"`
 }
 
 type RegisterInput struct {
@@ -19,21 +27,63 @@ type RegisterInput struct {
 }
 
 type RegisterOutput struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	User         User   `json:"user"`
 }
 
 type LoginInput struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	IP       string `json:"-"`
 }
 
 type LoginOutput struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	User         User   `json:"user"`
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type RefreshOutput struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+type LogoutInput struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type VerifyEmailInput struct {
 	Token string `json:"token"`
-	User  User   `json:"user"`
+}
+
+// AccessToken identifies the access token a request was authenticated with.
+type AccessToken struct {
+	UserID    int64
+	ID        string
+	ExpiresAt time.Time
 }
 
 type UserUseCase interface {
 	Register(ctx context.Context, input RegisterInput) (*RegisterOutput, utils.CustomError)
 	Login(ctx context.Context, input LoginInput) (*LoginOutput, utils.CustomError)
+	Refresh(ctx context.Context, input RefreshInput) (*RefreshOutput, utils.CustomError)
+	Logout(ctx context.Context, input LogoutInput, accessToken AccessToken) utils.CustomError
+	RevokeSessions(ctx context.Context, userID int64) utils.CustomError
+	ForgotPassword(ctx context.Context, input ForgotPasswordInput) utils.CustomError
+	ResetPassword(ctx context.Context, input ResetPasswordInput) utils.CustomError
+	VerifyEmail(ctx context.Context, input VerifyEmailInput) utils.CustomError
 }
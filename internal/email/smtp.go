@@ -0,0 +1,63 @@
+package email
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/domain/mailer"
+)
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a Mailer that delivers through an SMTP server. PLAIN authentication is
+// used when a username is configured.
+func NewSMTPMailer(cfg config.MailConfig) mailer.Mailer {
+	m := &SMTPMailer{
+		addr: net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		from: cfg.From,
+	}
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return m
+}
+
+// Send delivers the message.
+func (m *SMTPMailer) Send(ctx context.Context, msg mailer.Message) error {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "SMTPMailer.Send")
+	defer span.End()
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, formatMessage(m.from, msg)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to send email")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Email sent successfully")
+	return nil
+}
+
+// formatMessage renders the message as an RFC 5322 plain-text email.
+func formatMessage(from string, msg mailer.Message) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
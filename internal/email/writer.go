@@ -0,0 +1,46 @@
+package email
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/masatrio/bookstore-api/internal/domain/mailer"
+)
+
+type WriterMailer struct {
+	mu   sync.Mutex
+	from string
+	w    io.Writer
+}
+
+// NewWriterMailer creates a Mailer that writes each message to w instead of sending it.
+// It is meant for local development and tests.
+func NewWriterMailer(from string, w io.Writer) mailer.Mailer {
+	return &WriterMailer{
+		from: from,
+		w:    w,
+	}
+}
+
+// NewFileMailer creates a Mailer that appends each message to the file at path.
+func NewFileMailer(from, path string) (mailer.Mailer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterMailer(from, f), nil
+}
+
+// Send writes the message followed by a blank line.
+func (m *WriterMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := m.w.Write(formatMessage(m.from, msg)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(m.w, "\r\n\r\n")
+	return err
+}
@@ -0,0 +1,27 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/masatrio/bookstore-api/internal/domain/mailer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriterMailer(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriterMailer("noreply@bookstore.test", &buf)
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "satrio@test.test",
+		Subject: "Reset your password",
+		Body:    "line one\nline two",
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "From: noreply@bookstore.test\r\n")
+	assert.Contains(t, buf.String(), "To: satrio@test.test\r\n")
+	assert.Contains(t, buf.String(), "Subject: Reset your password\r\n")
+	assert.Contains(t, buf.String(), "\r\n\r\nline one\r\nline two")
+}
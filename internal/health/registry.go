@@ -0,0 +1,116 @@
+package health
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/health"
+)
+
+type component struct {
+	name     string
+	checker  health.HealthChecker
+	optional bool
+}
+
+// Registry runs the registered dependency checks for the readiness probe.
+type Registry struct {
+	timeout      time.Duration
+	mu           sync.RWMutex
+	components   []component
+	shuttingDown atomic.Bool
+}
+
+// NewRegistry creates a Registry that gives each check at most timeout to complete.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds a dependency that must be healthy for the service to be ready.
+func (r *Registry) Register(name string, checker health.HealthChecker) {
+	r.add(component{name: name, checker: checker})
+}
+
+// RegisterOptional adds a dependency whose status is reported without affecting readiness.
+func (r *Registry) RegisterOptional(name string, checker health.HealthChecker) {
+	r.add(component{name: name, checker: checker, optional: true})
+}
+
+func (r *Registry) add(c component) {
+	r.mu.Lock()
+	r.components = append(r.components, c)
+	r.mu.Unlock()
+}
+
+// MarkShuttingDown makes every following check report not ready, so that load balancers stop
+// sending new requests while in-flight ones drain.
+func (r *Registry) MarkShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Check runs all checks concurrently and reports whether the service is ready.
+func (r *Registry) Check(ctx context.Context) (health.Report, bool) {
+	if r.shuttingDown.Load() {
+		return health.Report{Status: health.StatusShuttingDown}, false
+	}
+
+	r.mu.RLock()
+	components := append([]component(nil), r.components...)
+	r.mu.RUnlock()
+
+	statuses := make([]health.ComponentStatus, len(components))
+	var wg sync.WaitGroup
+	for i, c := range components {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = r.check(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	report := health.Report{Status: health.StatusOK, Components: make(map[string]health.ComponentStatus, len(components))}
+	ready := true
+	for i, c := range components {
+		report.Components[c.name] = statuses[i]
+		if statuses[i].Status != health.StatusOK && !c.optional {
+			report.Status = health.StatusDown
+			ready = false
+		}
+	}
+	return report, ready
+}
+
+func (r *Registry) check(ctx context.Context, c component) health.ComponentStatus {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.checker.Check(ctx)
+	status := health.ComponentStatus{
+		Status:    health.StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Optional:  c.optional,
+	}
+	if err != nil {
+		status.Status = health.StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// NewTCPChecker checks that a TCP connection can be opened to addr, for dependencies such as an
+// OTLP collector that have no health API of their own.
+func NewTCPChecker(addr string) health.HealthChecker {
+	return health.HealthCheckerFunc(func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
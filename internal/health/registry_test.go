@@ -0,0 +1,53 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/health"
+)
+
+func TestRegistryCheck(t *testing.T) {
+	registry := NewRegistry(50 * time.Millisecond)
+	registry.Register("postgres", health.HealthCheckerFunc(func(ctx context.Context) error { return nil }))
+	registry.RegisterOptional("otlp", health.HealthCheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+
+	report, ready := registry.Check(context.Background())
+	assert.True(t, ready)
+	assert.Equal(t, health.StatusOK, report.Status)
+	assert.Equal(t, health.StatusOK, report.Components["postgres"].Status)
+	assert.Equal(t, health.StatusDown, report.Components["otlp"].Status)
+	assert.Equal(t, "connection refused", report.Components["otlp"].Error)
+
+	// A check that hangs is cut off by the timeout and makes the service unready.
+	registry.Register("redis", health.HealthCheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	report, ready = registry.Check(context.Background())
+	assert.False(t, ready)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Components["redis"].Error)
+
+	registry.MarkShuttingDown()
+	report, ready = registry.Check(context.Background())
+	assert.False(t, ready)
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+}
+
+func TestTCPChecker(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	checker := NewTCPChecker(listener.Addr().String())
+	assert.NoError(t, checker.Check(context.Background()))
+
+	listener.Close()
+	assert.Error(t, checker.Check(context.Background()))
+}
@@ -0,0 +1,45 @@
+package cached
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// CachedAuthorRepository decorates an AuthorRepository so that changes to book credits
+// invalidate the cached book listings, which can be filtered by author. Cache failures are
+// recorded on the span but never fail the underlying call.
+type CachedAuthorRepository struct {
+	repository.AuthorRepository
+	cache cache.BookCache
+}
+
+// NewCachedAuthorRepository creates a new instance of CachedAuthorRepository.
+func NewCachedAuthorRepository(repo repository.AuthorRepository, bookCache cache.BookCache) repository.AuthorRepository {
+	return &CachedAuthorRepository{
+		AuthorRepository: repo,
+		cache:            bookCache,
+	}
+}
+
+// SetBookAuthors replaces the book's contributors and invalidates cached listings.
+func (r *CachedAuthorRepository) SetBookAuthors(ctx context.Context, bookID int64, contributors []repository.BookAuthor) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedAuthorRepository.SetBookAuthors")
+	defer span.End()
+
+	if err := r.AuthorRepository.SetBookAuthors(ctx, bookID, contributors); err != nil {
+		return err
+	}
+
+	utils.AfterCommit(ctx, func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		if err := r.cache.InvalidateFiltered(ctx); err != nil {
+			span.RecordError(err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,151 @@
+package cached
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// CachedBookRepository is a cache-aside decorator around a BookRepository.
+// Reads are served from the cache when possible and writes invalidate the affected entries.
+// Cache failures are recorded on the span but never fail the underlying call.
+type CachedBookRepository struct {
+	repository.BookRepository
+	cache cache.BookCache
+}
+
+// NewCachedBookRepository creates a new instance of CachedBookRepository.
+func NewCachedBookRepository(repo repository.BookRepository, bookCache cache.BookCache) repository.BookRepository {
+	return &CachedBookRepository{
+		BookRepository: repo,
+		cache:          bookCache,
+	}
+}
+
+// CreateBook creates the book and invalidates cached listings.
+func (r *CachedBookRepository) CreateBook(ctx context.Context, book *repository.Book) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.CreateBook")
+	defer span.End()
+
+	id, err := r.BookRepository.CreateBook(ctx, book)
+	if err != nil {
+		return 0, err
+	}
+
+	utils.AfterCommit(ctx, func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		if err := r.cache.InvalidateFiltered(ctx); err != nil {
+			span.RecordError(err)
+		}
+	})
+
+	return id, nil
+}
+
+// GetBookByID returns the cached book, loading and caching it on a miss.
+func (r *CachedBookRepository) GetBookByID(ctx context.Context, bookID int64) (*repository.Book, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.GetBookByID")
+	defer span.End()
+
+	book, err := r.cache.GetBook(ctx, bookID)
+	if err != nil {
+		span.RecordError(err)
+	}
+	if book != nil {
+		return book, nil
+	}
+
+	book, err = r.BookRepository.GetBookByID(ctx, bookID)
+	if err != nil || book == nil {
+		return book, err
+	}
+
+	if err := r.cache.SetBook(ctx, book); err != nil {
+		span.RecordError(err)
+	}
+
+	return book, nil
+}
+
+// UpdateBook updates the book and invalidates its cached entry and cached listings.
+func (r *CachedBookRepository) UpdateBook(ctx context.Context, book *repository.Book) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.UpdateBook")
+	defer span.End()
+
+	if err := r.BookRepository.UpdateBook(ctx, book); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, book.ID)
+	return nil
+}
+
+// DeleteBook deletes the book and invalidates its cached entry and cached listings.
+func (r *CachedBookRepository) DeleteBook(ctx context.Context, bookID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.DeleteBook")
+	defer span.End()
+
+	if err := r.BookRepository.DeleteBook(ctx, bookID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, bookID)
+	return nil
+}
+
+// UpdateStock adjusts the book's stock and invalidates its cached entry and cached listings.
+func (r *CachedBookRepository) UpdateStock(ctx context.Context, bookID int64, delta int) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.UpdateStock")
+	defer span.End()
+
+	if err := r.BookRepository.UpdateStock(ctx, bookID, delta); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, bookID)
+	return nil
+}
+
+// GetFiltered returns the cached listing, loading and caching it on a miss.
+func (r *CachedBookRepository) GetFiltered(ctx context.Context, filter repository.BookFilter) ([]repository.Book, int, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedBookRepository.GetFiltered")
+	defer span.End()
+
+	list, err := r.cache.GetFiltered(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+	}
+	if list != nil {
+		return list.Books, list.Total, nil
+	}
+
+	books, total, err := r.BookRepository.GetFiltered(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := r.cache.SetFiltered(ctx, filter, &cache.BookList{Books: books, Total: total}); err != nil {
+		span.RecordError(err)
+	}
+
+	return books, total, nil
+}
+
+// invalidate drops the cached book and every cached listing once the transaction in ctx, if
+// any, commits. Dropping them earlier would let a concurrent read cache the pre-commit rows
+// again for the whole TTL.
+func (r *CachedBookRepository) invalidate(ctx context.Context, bookID int64) {
+	utils.AfterCommit(ctx, func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		if err := r.cache.DeleteBook(ctx, bookID); err != nil {
+			span.RecordError(err)
+		}
+		if err := r.cache.InvalidateFiltered(ctx); err != nil {
+			span.RecordError(err)
+		}
+	})
+}
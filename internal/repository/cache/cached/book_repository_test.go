@@ -0,0 +1,146 @@
+package cached
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/memory"
+)
+
+// stubBookRepository is an in-memory BookRepository that counts reads.
+type stubBookRepository struct {
+	books         map[int64]repository.Book
+	getByIDCalls  int
+	filteredCalls int
+}
+
+func (s *stubBookRepository) CreateBook(ctx context.Context, book *repository.Book) (int64, error) {
+	book.ID = int64(len(s.books) + 1)
+	s.books[book.ID] = *book
+	return book.ID, nil
+}
+
+func (s *stubBookRepository) GetBookByID(ctx context.Context, bookID int64) (*repository.Book, error) {
+	s.getByIDCalls++
+	book, ok := s.books[bookID]
+	if !ok {
+		return nil, nil
+	}
+	return &book, nil
+}
+
+func (s *stubBookRepository) UpdateBook(ctx context.Context, book *repository.Book) error {
+	s.books[book.ID] = *book
+	return nil
+}
+
+func (s *stubBookRepository) DeleteBook(ctx context.Context, bookID int64) error {
+	delete(s.books, bookID)
+	return nil
+}
+
+func (s *stubBookRepository) GetFiltered(ctx context.Context, filter repository.BookFilter) ([]repository.Book, int, error) {
+	s.filteredCalls++
+	var books []repository.Book
+	for _, book := range s.books {
+		books = append(books, book)
+	}
+	return books, len(books), nil
+}
+
+func (s *stubBookRepository) BookSearch(ctx context.Context, filter repository.BookSearchFilter) ([]repository.BookSearchResult, int, error) {
+	return nil, 0, nil
+}
+
+func (s *stubBookRepository) GetBooksByIDs(ctx context.Context, bookIDs []int64) ([]repository.Book, error) {
+	return s.GetBooksForUpdate(ctx, bookIDs)
+}
+
+func (s *stubBookRepository) GetBooksForUpdate(ctx context.Context, bookIDs []int64) ([]repository.Book, error) {
+	var books []repository.Book
+	for _, id := range bookIDs {
+		if book, ok := s.books[id]; ok {
+			books = append(books, book)
+		}
+	}
+	return books, nil
+}
+
+func (s *stubBookRepository) UpdateStock(ctx context.Context, bookID int64, delta int) error {
+	book := s.books[bookID]
+	if book.Stock+delta < 0 {
+		return repository.ErrInsufficientStock
+	}
+	book.Stock += delta
+	s.books[bookID] = book
+	return nil
+}
+
+func (s *stubBookRepository) GetStockLevels(ctx context.Context, filter repository.StockFilter) ([]repository.Book, int, error) {
+	return s.GetFiltered(ctx, repository.BookFilter{})
+}
+
+func TestCachedBookRepository_GetBookByID(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubBookRepository{books: map[int64]repository.Book{1: {ID: 1, Title: "The Hobbit"}}}
+	repo := NewCachedBookRepository(stub, memory.NewMemoryBookCache(0))
+
+	book, err := repo.GetBookByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Hobbit", book.Title)
+
+	book, err = repo.GetBookByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Hobbit", book.Title)
+	assert.Equal(t, 1, stub.getByIDCalls)
+
+	err = repo.UpdateBook(ctx, &repository.Book{ID: 1, Title: "The Lord of the Rings"})
+	assert.NoError(t, err)
+
+	book, err = repo.GetBookByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "The Lord of the Rings", book.Title)
+	assert.Equal(t, 2, stub.getByIDCalls)
+
+	err = repo.UpdateStock(ctx, 1, 5)
+	assert.NoError(t, err)
+
+	book, err = repo.GetBookByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, book.Stock)
+	assert.Equal(t, 3, stub.getByIDCalls)
+
+	err = repo.DeleteBook(ctx, 1)
+	assert.NoError(t, err)
+
+	book, err = repo.GetBookByID(ctx, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, book)
+}
+
+func TestCachedBookRepository_GetFiltered(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubBookRepository{books: map[int64]repository.Book{1: {ID: 1, Title: "The Hobbit"}}}
+	repo := NewCachedBookRepository(stub, memory.NewMemoryBookCache(0))
+	filter := repository.BookFilter{Limit: 10}
+
+	_, total, err := repo.GetFiltered(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	_, total, err = repo.GetFiltered(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, 1, stub.filteredCalls)
+
+	_, err = repo.CreateBook(ctx, &repository.Book{Title: "1984"})
+	assert.NoError(t, err)
+
+	_, total, err = repo.GetFiltered(ctx, filter)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, stub.filteredCalls)
+}
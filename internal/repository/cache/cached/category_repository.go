@@ -0,0 +1,76 @@
+package cached
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// CachedCategoryRepository decorates a CategoryRepository so that changes to the category tree
+// or to book assignments invalidate the cached book listings, which can be filtered by category.
+// Cache failures are recorded on the span but never fail the underlying call.
+type CachedCategoryRepository struct {
+	repository.CategoryRepository
+	cache cache.BookCache
+}
+
+// NewCachedCategoryRepository creates a new instance of CachedCategoryRepository.
+func NewCachedCategoryRepository(repo repository.CategoryRepository, bookCache cache.BookCache) repository.CategoryRepository {
+	return &CachedCategoryRepository{
+		CategoryRepository: repo,
+		cache:              bookCache,
+	}
+}
+
+// UpdateCategory updates the category and invalidates cached listings.
+func (r *CachedCategoryRepository) UpdateCategory(ctx context.Context, category *repository.Category) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedCategoryRepository.UpdateCategory")
+	defer span.End()
+
+	if err := r.CategoryRepository.UpdateCategory(ctx, category); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+// DeleteCategory deletes the category and invalidates cached listings.
+func (r *CachedCategoryRepository) DeleteCategory(ctx context.Context, categoryID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedCategoryRepository.DeleteCategory")
+	defer span.End()
+
+	if err := r.CategoryRepository.DeleteCategory(ctx, categoryID); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+// SetBookCategories replaces the book's categories and invalidates cached listings.
+func (r *CachedCategoryRepository) SetBookCategories(ctx context.Context, bookID int64, categoryIDs []int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedCategoryRepository.SetBookCategories")
+	defer span.End()
+
+	if err := r.CategoryRepository.SetBookCategories(ctx, bookID, categoryIDs); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+// invalidate drops every cached listing once the transaction in ctx, if any, commits.
+func (r *CachedCategoryRepository) invalidate(ctx context.Context) {
+	utils.AfterCommit(ctx, func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		if err := r.cache.InvalidateFiltered(ctx); err != nil {
+			span.RecordError(err)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package cached
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// CachedPublisherRepository decorates a PublisherRepository so that assigning a book to a
+// publisher invalidates the cached book listings, which can be filtered by publisher. Cache
+// failures are recorded on the span but never fail the underlying call.
+type CachedPublisherRepository struct {
+	repository.PublisherRepository
+	cache cache.BookCache
+}
+
+// NewCachedPublisherRepository creates a new instance of CachedPublisherRepository.
+func NewCachedPublisherRepository(repo repository.PublisherRepository, bookCache cache.BookCache) repository.PublisherRepository {
+	return &CachedPublisherRepository{
+		PublisherRepository: repo,
+		cache:               bookCache,
+	}
+}
+
+// SetBookPublisher assigns the book's publisher and invalidates cached listings.
+func (r *CachedPublisherRepository) SetBookPublisher(ctx context.Context, bookID int64, publisherID *int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "CachedPublisherRepository.SetBookPublisher")
+	defer span.End()
+
+	if err := r.PublisherRepository.SetBookPublisher(ctx, bookID, publisherID); err != nil {
+		return err
+	}
+
+	utils.AfterCommit(ctx, func(ctx context.Context) {
+		span := trace.SpanFromContext(ctx)
+		if err := r.cache.InvalidateFiltered(ctx); err != nil {
+			span.RecordError(err)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+type MemoryBookCache struct {
+	books *ttlMap[int64, repository.Book]
+	lists *ttlMap[repository.BookFilter, cache.BookList]
+}
+
+// NewMemoryBookCache creates a new in-process BookCache.
+func NewMemoryBookCache(ttl time.Duration) cache.BookCache {
+	return &MemoryBookCache{
+		books: newTTLMap[int64, repository.Book](ttl),
+		lists: newTTLMap[repository.BookFilter, cache.BookList](ttl),
+	}
+}
+
+// GetBook retrieves a cached book by its ID.
+func (c *MemoryBookCache) GetBook(ctx context.Context, bookID int64) (*repository.Book, error) {
+	book, ok := c.books.get(bookID)
+	if !ok {
+		return nil, nil
+	}
+	return &book, nil
+}
+
+// SetBook caches a book by its ID.
+func (c *MemoryBookCache) SetBook(ctx context.Context, book *repository.Book) error {
+	c.books.set(book.ID, *book)
+	return nil
+}
+
+// DeleteBook removes a cached book.
+func (c *MemoryBookCache) DeleteBook(ctx context.Context, bookID int64) error {
+	c.books.delete(bookID)
+	return nil
+}
+
+// GetFiltered retrieves a cached page of filtered books.
+func (c *MemoryBookCache) GetFiltered(ctx context.Context, filter repository.BookFilter) (*cache.BookList, error) {
+	list, ok := c.lists.get(filter)
+	if !ok {
+		return nil, nil
+	}
+	return &cache.BookList{
+		Books: append([]repository.Book(nil), list.Books...),
+		Total: list.Total,
+	}, nil
+}
+
+// SetFiltered caches a page of filtered books.
+func (c *MemoryBookCache) SetFiltered(ctx context.Context, filter repository.BookFilter, list *cache.BookList) error {
+	c.lists.set(filter, cache.BookList{
+		Books: append([]repository.Book(nil), list.Books...),
+		Total: list.Total,
+	})
+	return nil
+}
+
+// InvalidateFiltered drops every cached book listing.
+func (c *MemoryBookCache) InvalidateFiltered(ctx context.Context) error {
+	c.lists.clear()
+	return nil
+}
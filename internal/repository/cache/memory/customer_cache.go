@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+type MemoryCustomerCache struct {
+	customers *ttlMap[int64, repository.User]
+}
+
+// NewMemoryCustomerCache creates a new in-process CustomerCache.
+func NewMemoryCustomerCache(ttl time.Duration) cache.CustomerCache {
+	return &MemoryCustomerCache{
+		customers: newTTLMap[int64, repository.User](ttl),
+	}
+}
+
+// GetCustomer retrieves a cached customer by their ID.
+func (c *MemoryCustomerCache) GetCustomer(ctx context.Context, userID int64) (*repository.User, error) {
+	user, ok := c.customers.get(userID)
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// SetCustomer caches a customer by their ID.
+func (c *MemoryCustomerCache) SetCustomer(ctx context.Context, user *repository.User) error {
+	c.customers.set(user.ID, *user)
+	return nil
+}
+
+// DeleteCustomer removes a cached customer.
+func (c *MemoryCustomerCache) DeleteCustomer(ctx context.Context, userID int64) error {
+	c.customers.delete(userID)
+	return nil
+}
@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+type MemoryLoginAttemptStore struct {
+	mu       sync.Mutex
+	attempts *ttlMap[string, cache.LoginAttempts]
+}
+
+// NewMemoryLoginAttemptStore creates a new in-process LoginAttemptStore. Counts are per process,
+// so use the Redis store when running more than one replica.
+func NewMemoryLoginAttemptStore() cache.LoginAttemptStore {
+	return &MemoryLoginAttemptStore{
+		attempts: newTTLMap[string, cache.LoginAttempts](0),
+	}
+}
+
+// Get returns the failed-login state for a key.
+func (s *MemoryLoginAttemptStore) Get(ctx context.Context, key string) (cache.LoginAttempts, error) {
+	attempts, _ := s.attempts.get(key)
+	return attempts, nil
+}
+
+// RecordFailure increments the failure count for a key and returns the new state.
+func (s *MemoryLoginAttemptStore) RecordFailure(ctx context.Context, key string, retention time.Duration) (cache.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	attempts, _ := s.attempts.get(key)
+	attempts.Failures++
+	attempts.LastFailure = time.Now()
+	s.attempts.setUntil(key, attempts, attempts.LastFailure.Add(retention))
+	return attempts, nil
+}
+
+// Reset forgets the failed-login state for a key.
+func (s *MemoryLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.attempts.delete(key)
+	return nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often writes also remove the entries that have expired. Keys such as
+// listing filters, emails and IPs come from clients, so expired entries cannot be left to pile up.
+const sweepInterval = time.Minute
+
+// ttlMap is a concurrency-safe map whose entries expire after a fixed TTL.
+// A zero TTL keeps entries until they are deleted.
+type ttlMap[K comparable, V any] struct {
+	mu        sync.RWMutex
+	ttl       time.Duration
+	entries   map[K]ttlEntry[V]
+	nextSweep time.Time
+}
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+func newTTLMap[K comparable, V any](ttl time.Duration) *ttlMap[K, V] {
+	return &ttlMap[K, V]{
+		ttl:       ttl,
+		entries:   make(map[K]ttlEntry[V]),
+		nextSweep: time.Now().Add(sweepInterval),
+	}
+}
+
+func (m *ttlMap[K, V]) get(key K) (V, bool) {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	m.mu.RUnlock()
+
+	if !ok || (!entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt)) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+func (m *ttlMap[K, V]) set(key K, value V) {
+	now := time.Now()
+	entry := ttlEntry[V]{value: value}
+	if m.ttl > 0 {
+		entry.expiresAt = now.Add(m.ttl)
+	}
+
+	m.mu.Lock()
+	m.sweep(now)
+	m.entries[key] = entry
+	m.mu.Unlock()
+}
+
+// setUntil stores an entry that expires at the given time instead of after the map's TTL.
+func (m *ttlMap[K, V]) setUntil(key K, value V, expiresAt time.Time) {
+	m.mu.Lock()
+	m.sweep(time.Now())
+	m.entries[key] = ttlEntry[V]{value: value, expiresAt: expiresAt}
+	m.mu.Unlock()
+}
+
+// sweep removes the expired entries once every sweepInterval, spreading the cost over writes.
+// The caller must hold the write lock.
+func (m *ttlMap[K, V]) sweep(now time.Time) {
+	if now.Before(m.nextSweep) {
+		return
+	}
+	for key, entry := range m.entries {
+		if !entry.expiresAt.IsZero() && now.After(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+	}
+	m.nextSweep = now.Add(sweepInterval)
+}
+
+func (m *ttlMap[K, V]) delete(key K) {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+}
+
+func (m *ttlMap[K, V]) clear() {
+	m.mu.Lock()
+	m.entries = make(map[K]ttlEntry[V])
+	m.mu.Unlock()
+}
@@ -0,0 +1,27 @@
+package memory
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTTLMap_SweepsExpiredEntries(t *testing.T) {
+	m := newTTLMap[string, int](0)
+	m.setUntil("expired", 1, time.Now().Add(-time.Second))
+	m.setUntil("live", 2, time.Now().Add(time.Hour))
+	m.set("forever", 3)
+
+	// Expired entries stay until the next sweep is due.
+	assert.Len(t, m.entries, 3)
+	_, ok := m.get("expired")
+	assert.False(t, ok)
+
+	m.nextSweep = time.Now().Add(-time.Second)
+	m.set("new", 4)
+
+	assert.Len(t, m.entries, 3)
+	assert.NotContains(t, m.entries, "expired")
+	assert.True(t, m.nextSweep.After(time.Now()))
+}
@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+type MemoryOrderCache struct {
+	orders *ttlMap[int64, repository.Order]
+}
+
+// NewMemoryOrderCache creates a new in-process OrderCache.
+func NewMemoryOrderCache(ttl time.Duration) cache.OrderCache {
+	return &MemoryOrderCache{
+		orders: newTTLMap[int64, repository.Order](ttl),
+	}
+}
+
+// GetOrder retrieves a cached order by its ID.
+func (c *MemoryOrderCache) GetOrder(ctx context.Context, orderID int64) (*repository.Order, error) {
+	order, ok := c.orders.get(orderID)
+	if !ok {
+		return nil, nil
+	}
+	return &order, nil
+}
+
+// SetOrder caches an order by its ID.
+func (c *MemoryOrderCache) SetOrder(ctx context.Context, order *repository.Order) error {
+	c.orders.set(order.ID, *order)
+	return nil
+}
+
+// DeleteOrder removes a cached order.
+func (c *MemoryOrderCache) DeleteOrder(ctx context.Context, orderID int64) error {
+	c.orders.delete(orderID)
+	return nil
+}
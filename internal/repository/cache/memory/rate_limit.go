@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets *ttlMap[string, tokenBucket]
+}
+
+// NewMemoryRateLimitStore creates a new in-process RateLimitStore. Buckets are per process,
+// so use the Redis store when running more than one replica.
+func NewMemoryRateLimitStore() cache.RateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: newTTLMap[string, tokenBucket](0),
+	}
+}
+
+// Take removes a token from the bucket for key, refilling it for the time since the last request.
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (cache.RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := s.buckets.get(key)
+	if !ok {
+		bucket = tokenBucket{tokens: float64(burst), updated: now}
+	}
+
+	bucket.tokens = min(float64(burst), bucket.tokens+now.Sub(bucket.updated).Seconds()*rate)
+	bucket.updated = now
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens--
+	}
+
+	result := cache.NewRateLimitResult(allowed, bucket.tokens, rate, burst)
+	// A full bucket is the same as no bucket, so it can be dropped once it has refilled.
+	s.buckets.setUntil(key, bucket, now.Add(result.ResetAfter))
+	return result, nil
+}
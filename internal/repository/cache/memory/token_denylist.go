@@ -0,0 +1,32 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+type MemoryTokenDenylist struct {
+	tokens *ttlMap[string, struct{}]
+}
+
+// NewMemoryTokenDenylist creates a new in-process TokenDenylist. Revocations are only seen by
+// this process, so use the Redis denylist when running more than one replica.
+func NewMemoryTokenDenylist() cache.TokenDenylist {
+	return &MemoryTokenDenylist{
+		tokens: newTTLMap[string, struct{}](0),
+	}
+}
+
+// Deny revokes a token until it expires.
+func (d *MemoryTokenDenylist) Deny(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	d.tokens.setUntil(tokenID, struct{}{}, expiresAt)
+	return nil
+}
+
+// IsDenied reports whether a token has been revoked.
+func (d *MemoryTokenDenylist) IsDenied(ctx context.Context, tokenID string) (bool, error) {
+	_, denied := d.tokens.get(tokenID)
+	return denied, nil
+}
@@ -1 +1,168 @@
 package redis
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+const bookListVersionKey = "books:list:version"
+
+type RedisBookCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisBookCache creates a new instance of RedisBookCache.
+func NewRedisBookCache(client *redis.Client, ttl time.Duration) cache.BookCache {
+	return &RedisBookCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// GetBook retrieves a cached book by its ID.
+func (c *RedisBookCache) GetBook(ctx context.Context, bookID int64) (*repository.Book, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.GetBook")
+	defer span.End()
+
+	var book repository.Book
+	found, err := getJSON(ctx, c.client, bookKey(bookID), &book)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cached book")
+		return nil, err
+	}
+	if !found {
+		span.SetStatus(codes.Ok, "Cache miss")
+		return nil, nil
+	}
+
+	span.SetStatus(codes.Ok, "Cache hit")
+	return &book, nil
+}
+
+// SetBook caches a book by its ID.
+func (c *RedisBookCache) SetBook(ctx context.Context, book *repository.Book) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.SetBook")
+	defer span.End()
+
+	if err := setJSON(ctx, c.client, bookKey(book.ID), book, c.ttl); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to cache book")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book cached successfully")
+	return nil
+}
+
+// DeleteBook removes a cached book.
+func (c *RedisBookCache) DeleteBook(ctx context.Context, bookID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.DeleteBook")
+	defer span.End()
+
+	if err := c.client.Del(ctx, bookKey(bookID)).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cached book")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cached book deleted successfully")
+	return nil
+}
+
+// GetFiltered retrieves a cached page of filtered books.
+func (c *RedisBookCache) GetFiltered(ctx context.Context, filter repository.BookFilter) (*cache.BookList, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.GetFiltered")
+	defer span.End()
+
+	key, err := c.listKey(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to build list key")
+		return nil, err
+	}
+
+	var list cache.BookList
+	found, err := getJSON(ctx, c.client, key, &list)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cached book list")
+		return nil, err
+	}
+	if !found {
+		span.SetStatus(codes.Ok, "Cache miss")
+		return nil, nil
+	}
+
+	span.SetStatus(codes.Ok, "Cache hit")
+	return &list, nil
+}
+
+// SetFiltered caches a page of filtered books.
+func (c *RedisBookCache) SetFiltered(ctx context.Context, filter repository.BookFilter, list *cache.BookList) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.SetFiltered")
+	defer span.End()
+
+	key, err := c.listKey(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to build list key")
+		return err
+	}
+
+	if err := setJSON(ctx, c.client, key, list, c.ttl); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to cache book list")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book list cached successfully")
+	return nil
+}
+
+// InvalidateFiltered drops every cached book listing by bumping the list version,
+// so old entries are no longer addressed and simply expire.
+func (c *RedisBookCache) InvalidateFiltered(ctx context.Context) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisBookCache.InvalidateFiltered")
+	defer span.End()
+
+	if err := c.client.Incr(ctx, bookListVersionKey).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to invalidate book lists")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book lists invalidated successfully")
+	return nil
+}
+
+// listKey builds the cache key for a filter under the current list version.
+func (c *RedisBookCache) listKey(ctx context.Context, filter repository.BookFilter) (string, error) {
+	version, err := c.client.Get(ctx, bookListVersionKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", err
+	}
+
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("books:list:%d:%x", version, sha1.Sum(data)), nil
+}
+
+func bookKey(bookID int64) string {
+	return fmt.Sprintf("books:%d", bookID)
+}
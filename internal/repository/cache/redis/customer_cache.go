@@ -1 +1,82 @@
 package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+type RedisCustomerCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisCustomerCache creates a new instance of RedisCustomerCache.
+func NewRedisCustomerCache(client *redis.Client, ttl time.Duration) cache.CustomerCache {
+	return &RedisCustomerCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// GetCustomer retrieves a cached customer by their ID.
+func (c *RedisCustomerCache) GetCustomer(ctx context.Context, userID int64) (*repository.User, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisCustomerCache.GetCustomer")
+	defer span.End()
+
+	var user repository.User
+	found, err := getJSON(ctx, c.client, customerKey(userID), &user)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cached customer")
+		return nil, err
+	}
+	if !found {
+		span.SetStatus(codes.Ok, "Cache miss")
+		return nil, nil
+	}
+
+	span.SetStatus(codes.Ok, "Cache hit")
+	return &user, nil
+}
+
+// SetCustomer caches a customer by their ID.
+func (c *RedisCustomerCache) SetCustomer(ctx context.Context, user *repository.User) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisCustomerCache.SetCustomer")
+	defer span.End()
+
+	if err := setJSON(ctx, c.client, customerKey(user.ID), user, c.ttl); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to cache customer")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Customer cached successfully")
+	return nil
+}
+
+// DeleteCustomer removes a cached customer.
+func (c *RedisCustomerCache) DeleteCustomer(ctx context.Context, userID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisCustomerCache.DeleteCustomer")
+	defer span.End()
+
+	if err := c.client.Del(ctx, customerKey(userID)).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cached customer")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cached customer deleted successfully")
+	return nil
+}
+
+func customerKey(userID int64) string {
+	return fmt.Sprintf("customers:%d", userID)
+}
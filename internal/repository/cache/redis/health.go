@@ -0,0 +1,16 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/masatrio/bookstore-api/internal/domain/health"
+)
+
+// NewHealthChecker checks that Redis answers a PING.
+func NewHealthChecker(client *redis.Client) health.HealthChecker {
+	return health.HealthCheckerFunc(func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+}
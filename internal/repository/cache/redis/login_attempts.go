@@ -0,0 +1,104 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+type RedisLoginAttemptStore struct {
+	client *redis.Client
+}
+
+// NewRedisLoginAttemptStore creates a new instance of RedisLoginAttemptStore.
+func NewRedisLoginAttemptStore(client *redis.Client) cache.LoginAttemptStore {
+	return &RedisLoginAttemptStore{
+		client: client,
+	}
+}
+
+// Get returns the failed-login state for a key.
+func (s *RedisLoginAttemptStore) Get(ctx context.Context, key string) (cache.LoginAttempts, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisLoginAttemptStore.Get")
+	defer span.End()
+
+	fields, err := s.client.HGetAll(ctx, loginAttemptsKey(key)).Result()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get login attempts")
+		return cache.LoginAttempts{}, err
+	}
+
+	attempts, err := parseLoginAttempts(fields["failures"], fields["last_failure"])
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to parse login attempts")
+		return cache.LoginAttempts{}, err
+	}
+
+	span.SetStatus(codes.Ok, "Login attempts retrieved successfully")
+	return attempts, nil
+}
+
+// RecordFailure increments the failure count for a key and returns the new state.
+func (s *RedisLoginAttemptStore) RecordFailure(ctx context.Context, key string, retention time.Duration) (cache.LoginAttempts, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisLoginAttemptStore.RecordFailure")
+	defer span.End()
+
+	now := time.Now()
+	redisKey := loginAttemptsKey(key)
+
+	pipe := s.client.TxPipeline()
+	failures := pipe.HIncrBy(ctx, redisKey, "failures", 1)
+	pipe.HSet(ctx, redisKey, "last_failure", now.UnixNano())
+	pipe.Expire(ctx, redisKey, retention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to record login failure")
+		return cache.LoginAttempts{}, err
+	}
+
+	span.SetStatus(codes.Ok, "Login failure recorded successfully")
+	return cache.LoginAttempts{Failures: int(failures.Val()), LastFailure: now}, nil
+}
+
+// Reset forgets the failed-login state for a key.
+func (s *RedisLoginAttemptStore) Reset(ctx context.Context, key string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisLoginAttemptStore.Reset")
+	defer span.End()
+
+	if err := s.client.Del(ctx, loginAttemptsKey(key)).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to reset login attempts")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Login attempts reset successfully")
+	return nil
+}
+
+func parseLoginAttempts(failures, lastFailure string) (cache.LoginAttempts, error) {
+	if failures == "" {
+		return cache.LoginAttempts{}, nil
+	}
+
+	count, err := strconv.Atoi(failures)
+	if err != nil {
+		return cache.LoginAttempts{}, err
+	}
+	nanos, err := strconv.ParseInt(lastFailure, 10, 64)
+	if err != nil {
+		return cache.LoginAttempts{}, err
+	}
+	return cache.LoginAttempts{Failures: count, LastFailure: time.Unix(0, nanos)}, nil
+}
+
+func loginAttemptsKey(key string) string {
+	return "login_attempts:" + key
+}
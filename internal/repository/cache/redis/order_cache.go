@@ -1 +1,82 @@
 package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+type RedisOrderCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisOrderCache creates a new instance of RedisOrderCache.
+func NewRedisOrderCache(client *redis.Client, ttl time.Duration) cache.OrderCache {
+	return &RedisOrderCache{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// GetOrder retrieves a cached order by its ID.
+func (c *RedisOrderCache) GetOrder(ctx context.Context, orderID int64) (*repository.Order, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisOrderCache.GetOrder")
+	defer span.End()
+
+	var order repository.Order
+	found, err := getJSON(ctx, c.client, orderKey(orderID), &order)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cached order")
+		return nil, err
+	}
+	if !found {
+		span.SetStatus(codes.Ok, "Cache miss")
+		return nil, nil
+	}
+
+	span.SetStatus(codes.Ok, "Cache hit")
+	return &order, nil
+}
+
+// SetOrder caches an order by its ID.
+func (c *RedisOrderCache) SetOrder(ctx context.Context, order *repository.Order) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisOrderCache.SetOrder")
+	defer span.End()
+
+	if err := setJSON(ctx, c.client, orderKey(order.ID), order, c.ttl); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to cache order")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Order cached successfully")
+	return nil
+}
+
+// DeleteOrder removes a cached order.
+func (c *RedisOrderCache) DeleteOrder(ctx context.Context, orderID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisOrderCache.DeleteOrder")
+	defer span.End()
+
+	if err := c.client.Del(ctx, orderKey(orderID)).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete cached order")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cached order deleted successfully")
+	return nil
+}
+
+func orderKey(orderID int64) string {
+	return fmt.Sprintf("orders:%d", orderID)
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+// takeTokenScript refills and takes from a token bucket atomically. It uses the Redis clock so
+// that replicas with skewed clocks share the same buckets consistently.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1])
+local updated = tonumber(bucket[2])
+if tokens == nil or updated == nil then
+	tokens = burst
+	updated = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate * 1000) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimitStore creates a new instance of RedisRateLimitStore.
+func NewRedisRateLimitStore(client *redis.Client) cache.RateLimitStore {
+	return &RedisRateLimitStore{
+		client: client,
+	}
+}
+
+// Take removes a token from the bucket for key, refilling it for the time since the last request.
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (cache.RateLimitResult, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisRateLimitStore.Take")
+	defer span.End()
+
+	reply, err := takeTokenScript.Run(ctx, s.client, []string{rateLimitKey(key)}, rate, burst).Slice()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to take rate limit token")
+		return cache.RateLimitResult{}, err
+	}
+
+	allowed, _ := reply[0].(int64)
+	tokensStr, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to parse rate limit bucket")
+		return cache.RateLimitResult{}, err
+	}
+
+	span.SetStatus(codes.Ok, "Rate limit token taken")
+	return cache.NewRateLimitResult(allowed == 1, tokens, rate, burst), nil
+}
+
+func rateLimitKey(key string) string {
+	return "rate_limit:" + key
+}
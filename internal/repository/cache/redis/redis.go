@@ -0,0 +1,60 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/masatrio/bookstore-api/config"
+)
+
+// NewClient creates a Redis client from the given config. The URL may either be a
+// redis:// URL or a plain host:port address.
+func NewClient(cfg config.RedisConfig) (*redis.Client, error) {
+	opts := &redis.Options{Addr: cfg.URL}
+	if strings.HasPrefix(cfg.URL, "redis://") || strings.HasPrefix(cfg.URL, "rediss://") {
+		parsed, err := redis.ParseURL(cfg.URL)
+		if err != nil {
+			return nil, err
+		}
+		opts = parsed
+	}
+
+	if cfg.Password != "" {
+		opts.Password = cfg.Password
+	}
+	if cfg.DB != 0 {
+		opts.DB = cfg.DB
+	}
+
+	return redis.NewClient(opts), nil
+}
+
+// getJSON reads the key and decodes it into dest. It reports false on a cache miss.
+func getJSON(ctx context.Context, client *redis.Client, key string, dest interface{}) (bool, error) {
+	data, err := client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := json.Unmarshal(data, dest); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// setJSON encodes value as JSON and stores it under the key with the given TTL.
+func setJSON(ctx context.Context, client *redis.Client, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return client.Set(ctx, key, data, ttl).Err()
+}
@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+)
+
+type RedisTokenDenylist struct {
+	client *redis.Client
+}
+
+// NewRedisTokenDenylist creates a new instance of RedisTokenDenylist.
+func NewRedisTokenDenylist(client *redis.Client) cache.TokenDenylist {
+	return &RedisTokenDenylist{
+		client: client,
+	}
+}
+
+// Deny revokes a token until it expires. Tokens that have already expired are skipped.
+func (d *RedisTokenDenylist) Deny(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisTokenDenylist.Deny")
+	defer span.End()
+
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		span.SetStatus(codes.Ok, "Token already expired")
+		return nil
+	}
+
+	if err := d.client.Set(ctx, deniedTokenKey(tokenID), 1, ttl).Err(); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to deny token")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Token denied successfully")
+	return nil
+}
+
+// IsDenied reports whether a token has been revoked.
+func (d *RedisTokenDenylist) IsDenied(ctx context.Context, tokenID string) (bool, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RedisTokenDenylist.IsDenied")
+	defer span.End()
+
+	count, err := d.client.Exists(ctx, deniedTokenKey(tokenID)).Result()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to check token denylist")
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "Token denylist checked")
+	return count > 0, nil
+}
+
+func deniedTokenKey(tokenID string) string {
+	return "denied_tokens:" + tokenID
+}
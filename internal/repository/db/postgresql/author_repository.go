@@ -0,0 +1,175 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresAuthorRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresAuthorRepository creates a new instance of PostgresAuthorRepository.
+func NewPostgresAuthorRepository(db *sql.DB, timeout time.Duration) repository.AuthorRepository {
+	return &PostgresAuthorRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// GetAuthorByID retrieves an author by its ID.
+func (r *PostgresAuthorRepository) GetAuthorByID(ctx context.Context, authorID int64) (*repository.Author, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresAuthorRepository.GetAuthorByID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, name, created_at, updated_at
+		      FROM authors
+		      WHERE id = $1`
+
+	author := &repository.Author{}
+	err := r.db.QueryRowContext(ctx, query, authorID).Scan(&author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Author not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get author by ID")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Author retrieved successfully")
+	return author, nil
+}
+
+// GetOrCreateAuthor returns the author with the given name, creating it when no author's name
+// matches it. Names match regardless of case, spacing and punctuation, and an existing author
+// keeps the spelling it was created with.
+func (r *PostgresAuthorRepository) GetOrCreateAuthor(ctx context.Context, name string) (*repository.Author, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresAuthorRepository.GetOrCreateAuthor")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The no-op update makes RETURNING yield the existing row on a conflict.
+	query := `INSERT INTO authors (name, created_at, updated_at)
+		      VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		      ON CONFLICT (name_key) DO UPDATE SET name = authors.name
+		      RETURNING id, name, created_at, updated_at`
+
+	author := &repository.Author{}
+	err := utils.QueryRowContext(ctx, r.db, query, name).Scan(&author.ID, &author.Name, &author.CreatedAt, &author.UpdatedAt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get or create author")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Author retrieved successfully")
+	return author, nil
+}
+
+// SetBookAuthors replaces the contributors credited on a book, positioning them in the order
+// given. Run it in a transaction so that the book is never left without its credits. It returns
+// repository.ErrAuthorNotFound when one of the authors does not exist.
+func (r *PostgresAuthorRepository) SetBookAuthors(ctx context.Context, bookID int64, contributors []repository.BookAuthor) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresAuthorRepository.SetBookAuthors")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, `DELETE FROM book_authors WHERE book_id = $1`, bookID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to clear book authors")
+		return err
+	}
+
+	if len(contributors) > 0 {
+		authorIDs := make([]int64, len(contributors))
+		roles := make([]string, len(contributors))
+		positions := make([]int64, len(contributors))
+		for i, contributor := range contributors {
+			authorIDs[i] = contributor.AuthorID
+			roles[i] = contributor.Role
+			positions[i] = int64(i + 1)
+		}
+
+		query := `INSERT INTO book_authors (book_id, author_id, role, position)
+			      SELECT $1, c.author_id, c.role, c.position
+			      FROM unnest($2::int[], $3::text[], $4::int[]) AS c(author_id, role, position)
+			      ON CONFLICT DO NOTHING`
+
+		if _, err := utils.PrepareAndExecContext(ctx, r.db, query, bookID, pq.Array(authorIDs), pq.Array(roles), pq.Array(positions)); err != nil {
+			if hasPQCode(err, pqForeignKeyViolation) {
+				span.SetStatus(codes.Error, "Author not found")
+				return repository.ErrAuthorNotFound
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to set book authors")
+			return err
+		}
+	}
+
+	span.SetStatus(codes.Ok, "Book authors set successfully")
+	return nil
+}
+
+// GetBookAuthors retrieves the contributors credited on the given books, ordered by book and
+// then by position.
+func (r *PostgresAuthorRepository) GetBookAuthors(ctx context.Context, bookIDs []int64) ([]repository.BookAuthor, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresAuthorRepository.GetBookAuthors")
+	defer span.End()
+
+	if len(bookIDs) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT ba.book_id, a.id, a.name, ba.role, ba.position
+		      FROM book_authors ba
+		      JOIN authors a ON a.id = ba.author_id
+		      WHERE ba.book_id = ANY($1)
+		      ORDER BY ba.book_id, ba.position, a.name`
+
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, pq.Array(bookIDs))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get book authors")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var contributors []repository.BookAuthor
+	for rows.Next() {
+		var contributor repository.BookAuthor
+		if err := rows.Scan(&contributor.BookID, &contributor.AuthorID, &contributor.Name, &contributor.Role, &contributor.Position); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		contributors = append(contributors, contributor)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Book authors retrieved successfully")
+	return contributors, nil
+}
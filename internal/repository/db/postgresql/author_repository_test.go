@@ -0,0 +1,66 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+func TestPostgresAuthorRepository_SetBookAuthors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresAuthorRepository(db, time.Second)
+	contributors := []repository.BookAuthor{
+		{AuthorID: 3, Role: repository.RoleAuthor},
+		{AuthorID: 8, Role: repository.RoleTranslator},
+	}
+
+	// Contributors are positioned in the order they are given.
+	mock.ExpectPrepare(`DELETE FROM book_authors`).ExpectExec().
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`INSERT INTO book_authors`).ExpectExec().
+		WithArgs(7, pq.Array([]int64{3, 8}), pq.Array([]string{"author", "translator"}), pq.Array([]int64{1, 2})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	assert.NoError(t, repo.SetBookAuthors(context.Background(), 7, contributors))
+
+	mock.ExpectPrepare(`DELETE FROM book_authors`).ExpectExec().
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectPrepare(`INSERT INTO book_authors`).ExpectExec().
+		WithArgs(7, pq.Array([]int64{3, 8}), pq.Array([]string{"author", "translator"}), pq.Array([]int64{1, 2})).
+		WillReturnError(&pq.Error{Code: pqForeignKeyViolation})
+	assert.ErrorIs(t, repo.SetBookAuthors(context.Background(), 7, contributors), repository.ErrAuthorNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresAuthorRepository_GetOrCreateAuthor_CanceledContext(t *testing.T) {
+	db, _, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A statement that cannot run must come back as an error rather than a panic.
+	author, err := NewPostgresAuthorRepository(db, time.Second).GetOrCreateAuthor(ctx, "J.R.R. Tolkien")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, author)
+
+	publisher, err := NewPostgresPublisherRepository(db, time.Second).GetOrCreatePublisher(ctx, "Allen & Unwin")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, publisher)
+}
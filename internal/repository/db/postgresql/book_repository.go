@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
@@ -14,13 +16,15 @@ import (
 )
 
 type PostgresBookRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
 // NewPostgresBookRepository creates a new instance of PostgresBookRepository.
-func NewPostgresBookRepository(db *sql.DB) repository.BookRepository {
+func NewPostgresBookRepository(db *sql.DB, timeout time.Duration) repository.BookRepository {
 	return &PostgresBookRepository{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
@@ -29,10 +33,13 @@ func (r *PostgresBookRepository) CreateBook(ctx context.Context, book *repositor
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.CreateBook")
 	defer span.End()
 
-	query := `INSERT INTO books (title, author, price, created_at, updated_at) 
-		      VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
 
-	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, book.Title, book.Author, book.Price)
+	query := `INSERT INTO books (title, author, price, stock, created_at, updated_at) 
+		      VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, book.Title, book.Author, book.Price, book.Stock)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to create book")
@@ -48,13 +55,16 @@ func (r *PostgresBookRepository) GetBookByID(ctx context.Context, bookID int64)
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.GetBookByID")
 	defer span.End()
 
-	query := `SELECT id, title, author, price, created_at, updated_at 
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, title, author, price, stock, created_at, updated_at 
 			  FROM books 
 			  WHERE id = $1`
 
 	book := &repository.Book{}
 	err := r.db.QueryRowContext(ctx, query, bookID).Scan(
-		&book.ID, &book.Title, &book.Author, &book.Price, &book.CreatedAt, &book.UpdatedAt,
+		&book.ID, &book.Title, &book.Author, &book.Price, &book.Stock, &book.CreatedAt, &book.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -70,92 +80,434 @@ func (r *PostgresBookRepository) GetBookByID(ctx context.Context, bookID int64)
 	return book, nil
 }
 
-// GetFiltered retrieves books with filters and pagination.
+// UpdateBook updates an existing book's title, author and price. It joins the transaction on
+// ctx, if any.
+func (r *PostgresBookRepository) UpdateBook(ctx context.Context, book *repository.Book) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.UpdateBook")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE books 
+		      SET title = $1, author = $2, price = $3, updated_at = CURRENT_TIMESTAMP 
+		      WHERE id = $4`
+
+	_, err := utils.PrepareAndExecContext(ctx, r.db, query, book.Title, book.Author, book.Price, book.ID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update book")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book updated successfully")
+	return nil
+}
+
+// DeleteBook removes a book by its ID.
+func (r *PostgresBookRepository) DeleteBook(ctx context.Context, bookID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.DeleteBook")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM books WHERE id = $1`
+
+	_, err := utils.PrepareAndExecContext(ctx, r.db, query, bookID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete book")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book deleted successfully")
+	return nil
+}
+
+// GetFiltered retrieves books with filters and pagination, ordered by filter.Sort. When a cursor
+// is given, the page starts after the book it was issued for instead of at the offset.
 func (r *PostgresBookRepository) GetFiltered(ctx context.Context, filter repository.BookFilter) ([]repository.Book, int, error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.GetFiltered")
 	defer span.End()
 
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if filter.Limit < 0 {
+		err := fmt.Errorf("invalid limit: %d", filter.Limit)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid limit")
+		return nil, 0, err
+	}
+	if filter.Offset < 0 {
+		err := fmt.Errorf("invalid offset: %d", filter.Offset)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid offset")
+		return nil, 0, err
+	}
+
+	fields, err := repository.ParseSort(filter.Sort, repository.BookSortFields)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid sort")
+		return nil, 0, err
+	}
+	keys := sortKeys(fields)
+
+	conditions, params := bookFilterConditions(filter, nil)
+
+	countQuery := `SELECT COUNT(*) FROM books`
+	if len(conditions) > 0 {
+		countQuery += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	err = r.db.QueryRowContext(ctx, countQuery, params...).Scan(&total)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to count books")
+		return nil, 0, err
+	}
+
+	offset := filter.Offset
+	if filter.Cursor != "" {
+		var keyset string
+		keyset, params, err = keysetCondition(keys, filter.Cursor, filter.Sort, params)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Invalid cursor")
+			return nil, 0, err
+		}
+		conditions = append(conditions, keyset)
+		offset = 0
+	}
+
+	query := `SELECT id, title, author, price, stock, created_at, updated_at FROM books`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += orderByClause(keys) + fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(params)+1, len(params)+2)
+	params = append(params, filter.Limit, offset)
+
+	// Fetch filtered books with limit/offset
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to fetch filtered books")
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var books []repository.Book
+	for rows.Next() {
+		var book repository.Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Price, &book.Stock, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			span.RecordError(err)
+			return nil, 0, err
+		}
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Filtered books retrieved successfully")
+	return books, total, nil
+}
+
+// bookHighlightOptions marks the matched terms in search results and keeps the whole field.
+const bookHighlightOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+
+// escapedHTML wraps a text column in SQL that escapes it for HTML before ts_headline runs, so
+// that the <mark> tags are the only markup in a highlight. The parser reads the entities as
+// single tokens, which leaves the matched words intact.
+func escapedHTML(column string) string {
+	return `replace(replace(replace(replace(replace(` + column +
+		`, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// BookSearch runs a full-text query against the title and author, ranked by ts_rank. Books
+// whose title or author is a close trigram match are included too, so small typos still
+// find results.
+func (r *PostgresBookRepository) BookSearch(ctx context.Context, filter repository.BookSearchFilter) ([]repository.BookSearchResult, int, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.BookSearch")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if filter.Limit < 0 || filter.Offset < 0 {
+		err := fmt.Errorf("invalid limit %d or offset %d", filter.Limit, filter.Offset)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid pagination")
+		return nil, 0, err
+	}
+
+	// $1 is the raw query text.
+	conditions, params := bookFilterConditions(filter.BookFilter, []interface{}{filter.Query})
+	conditions = append([]string{
+		"(search_vector @@ websearch_to_tsquery('english', $1) OR $1 <% title OR $1 <% author)",
+	}, conditions...)
+	where := " WHERE " + strings.Join(conditions, " AND ")
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM books`+where, params...).Scan(&total); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to count search results")
+		return nil, 0, err
+	}
+
+	query := `SELECT id, title, author, price, stock, created_at, updated_at,
+		      ts_rank(search_vector, websearch_to_tsquery('english', $1)) AS rank,
+		      ts_headline('english', ` + escapedHTML("title") + `, websearch_to_tsquery('english', $1), '` + bookHighlightOptions + `'),
+		      ts_headline('english', ` + escapedHTML("author") + `, websearch_to_tsquery('english', $1), '` + bookHighlightOptions + `')
+		      FROM books` + where + `
+		      ORDER BY rank DESC, GREATEST(word_similarity($1, title), word_similarity($1, author)) DESC, id` +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(params)+1, len(params)+2)
+	params = append(params, filter.Limit, filter.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to search books")
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []repository.BookSearchResult
+	for rows.Next() {
+		var result repository.BookSearchResult
+		if err := rows.Scan(&result.ID, &result.Title, &result.Author, &result.Price, &result.Stock, &result.CreatedAt, &result.UpdatedAt,
+			&result.Rank, &result.TitleHighlight, &result.AuthorHighlight); err != nil {
+			span.RecordError(err)
+			return nil, 0, err
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Books searched successfully")
+	return results, total, nil
+}
+
+// categorySubtreeQuery selects the ID of the category whose slug is in its placeholder, followed
+// by the IDs of all of its descendants. UNION stops the recursion even if the tree ever contains
+// a cycle.
+const categorySubtreeQuery = `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE slug = $%d
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	) SELECT id FROM subtree`
+
+// bookFilterConditions builds the WHERE conditions for the listing filters, numbering their
+// placeholders after the given params.
+func bookFilterConditions(filter repository.BookFilter, params []interface{}) ([]string, []interface{}) {
 	var conditions []string
-	var params []interface{}
-	var paramCounter = 1
+	add := func(condition string, value interface{}) {
+		params = append(params, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(params)))
+	}
 
-	// Dynamic filters based on provided input
 	if filter.Title != "" {
-		conditions = append(conditions, fmt.Sprintf("title ILIKE $%d", paramCounter))
-		params = append(params, "%"+filter.Title+"%")
-		paramCounter++
+		add("title ILIKE $%d", "%"+filter.Title+"%")
 	}
 	if filter.Author != "" {
-		conditions = append(conditions, fmt.Sprintf("author ILIKE $%d", paramCounter))
-		params = append(params, "%"+filter.Author+"%")
-		paramCounter++
+		// The credit line keeps matching books whose authors are spelled differently there.
+		add("(author ILIKE $%[1]d OR id IN (SELECT ba.book_id FROM book_authors ba JOIN authors a ON a.id = ba.author_id WHERE a.name ILIKE $%[1]d))", "%"+filter.Author+"%")
 	}
 	if filter.MinPrice > 0 {
-		conditions = append(conditions, fmt.Sprintf("price >= $%d", paramCounter))
-		params = append(params, filter.MinPrice)
-		paramCounter++
+		add("price >= $%d", filter.MinPrice)
 	}
 	if filter.MaxPrice > 0 {
-		conditions = append(conditions, fmt.Sprintf("price <= $%d", paramCounter))
-		params = append(params, filter.MaxPrice)
-		paramCounter++
+		add("price <= $%d", filter.MaxPrice)
 	}
 	if !filter.StartDate.IsZero() {
-		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", paramCounter))
-		params = append(params, filter.StartDate)
-		paramCounter++
+		add("created_at >= $%d", filter.StartDate)
 	}
 	if !filter.EndDate.IsZero() {
-		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", paramCounter))
-		params = append(params, filter.EndDate)
-		paramCounter++
+		add("created_at <= $%d", filter.EndDate)
+	}
+	if filter.Category != "" {
+		add("id IN (SELECT book_id FROM book_categories WHERE category_id IN ("+categorySubtreeQuery+"))", filter.Category)
+	}
+	if filter.AuthorID != 0 {
+		add("id IN (SELECT book_id FROM book_authors WHERE author_id = $%d)", filter.AuthorID)
+	}
+	if filter.PublisherID != 0 {
+		add("publisher_id = $%d", filter.PublisherID)
 	}
 
-	query := `SELECT id, title, author, price, created_at, updated_at FROM books`
-	countQuery := `SELECT COUNT(*) FROM books`
+	return conditions, params
+}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
-		countQuery += " WHERE " + strings.Join(conditions, " AND ")
+// GetBooksForUpdate retrieves the given books and locks their rows until the surrounding
+// transaction ends. Rows are locked in ID order so concurrent orders cannot deadlock.
+func (r *PostgresBookRepository) GetBooksForUpdate(ctx context.Context, bookIDs []int64) ([]repository.Book, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.GetBooksForUpdate")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, title, author, price, stock, created_at, updated_at 
+		      FROM books 
+		      WHERE id = ANY($1) 
+		      ORDER BY id 
+		      FOR UPDATE`
+
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, pq.Array(bookIDs))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to lock books")
+		return nil, err
 	}
+	defer rows.Close()
 
-	if filter.Limit < 0 {
-		err := fmt.Errorf("invalid limit: %d", filter.Limit)
+	var books []repository.Book
+	for rows.Next() {
+		var book repository.Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Price, &book.Stock, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		books = append(books, book)
+	}
+
+	if err := rows.Err(); err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Invalid limit")
-		return nil, 0, err
+		return nil, err
 	}
-	if filter.Offset < 0 {
-		err := fmt.Errorf("invalid offset: %d", filter.Offset)
+
+	span.SetStatus(codes.Ok, "Books locked successfully")
+	return books, nil
+}
+
+// GetBooksByIDs retrieves the given books in ID order. IDs that do not exist are skipped.
+func (r *PostgresBookRepository) GetBooksByIDs(ctx context.Context, bookIDs []int64) ([]repository.Book, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.GetBooksByIDs")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, title, author, price, stock, created_at, updated_at 
+		      FROM books 
+		      WHERE id = ANY($1) 
+		      ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(bookIDs))
+	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Invalid offset")
-		return nil, 0, err
+		span.SetStatus(codes.Error, "Failed to get books")
+		return nil, err
 	}
+	defer rows.Close()
 
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", paramCounter, paramCounter+1)
-	params = append(params, filter.Limit, filter.Offset)
+	var books []repository.Book
+	for rows.Next() {
+		var book repository.Book
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Price, &book.Stock, &book.CreatedAt, &book.UpdatedAt); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		books = append(books, book)
+	}
 
-	var total int
-	countArgs := make([]interface{}, 0, paramCounter-1)
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Books retrieved successfully")
+	return books, nil
+}
 
-	for i := 0; i < len(params); i++ {
-		if i < paramCounter-1 {
-			countArgs = append(countArgs, params[i])
+// UpdateStock adds delta (which may be negative) to a book's stock.
+// It returns repository.ErrInsufficientStock when the stock would drop below zero and
+// repository.ErrBookNotFound when the book does not exist.
+func (r *PostgresBookRepository) UpdateStock(ctx context.Context, bookID int64, delta int) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.UpdateStock")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE books 
+		      SET stock = stock + $1, updated_at = CURRENT_TIMESTAMP 
+		      WHERE id = $2 AND stock + $1 >= 0`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, delta, bookID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update stock")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update stock")
+		return err
+	}
+	if affected == 0 {
+		// No row matched either because the book is gone or because the stock would go negative.
+		var exists bool
+		err := utils.QueryRowContext(ctx, r.db, `SELECT EXISTS (SELECT 1 FROM books WHERE id = $1)`, bookID).Scan(&exists)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to update stock")
+			return err
+		}
+		if !exists {
+			span.SetStatus(codes.Error, "Book not found")
+			return repository.ErrBookNotFound
 		}
+		span.SetStatus(codes.Error, "Insufficient stock")
+		return repository.ErrInsufficientStock
 	}
 
-	err := r.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	span.SetStatus(codes.Ok, "Stock updated successfully")
+	return nil
+}
+
+// GetStockLevels retrieves books ordered by ascending stock, optionally only those at or below MaxStock.
+func (r *PostgresBookRepository) GetStockLevels(ctx context.Context, filter repository.StockFilter) ([]repository.Book, int, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresBookRepository.GetStockLevels")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	where := ""
+	var params []interface{}
+	if filter.MaxStock != nil {
+		where = " WHERE stock <= $1"
+		params = append(params, *filter.MaxStock)
+	}
+
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM books`+where, params...).Scan(&total)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to count books")
 		return nil, 0, err
 	}
 
-	// Fetch filtered books with limit/offset
+	query := `SELECT id, title, author, price, stock, created_at, updated_at FROM books` + where +
+		fmt.Sprintf(" ORDER BY stock ASC, id ASC LIMIT $%d OFFSET $%d", len(params)+1, len(params)+2)
+	params = append(params, filter.Limit, filter.Offset)
+
 	rows, err := r.db.QueryContext(ctx, query, params...)
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to fetch filtered books")
+		span.SetStatus(codes.Error, "Failed to fetch stock levels")
 		return nil, 0, err
 	}
 	defer rows.Close()
@@ -163,7 +515,7 @@ func (r *PostgresBookRepository) GetFiltered(ctx context.Context, filter reposit
 	var books []repository.Book
 	for rows.Next() {
 		var book repository.Book
-		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Price, &book.CreatedAt, &book.UpdatedAt); err != nil {
+		if err := rows.Scan(&book.ID, &book.Title, &book.Author, &book.Price, &book.Stock, &book.CreatedAt, &book.UpdatedAt); err != nil {
 			span.RecordError(err)
 			return nil, 0, err
 		}
@@ -175,6 +527,6 @@ func (r *PostgresBookRepository) GetFiltered(ctx context.Context, filter reposit
 		return nil, 0, err
 	}
 
-	span.SetStatus(codes.Ok, "Filtered books retrieved successfully")
+	span.SetStatus(codes.Ok, "Stock levels retrieved successfully")
 	return books, total, nil
 }
@@ -0,0 +1,206 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+func TestPostgresBookRepository_BookSearch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE \(search_vector @@ websearch_to_tsquery\('english', \$1\) (.+)\) AND price <= \$2`).
+		WithArgs("hobit", 20.0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	// Titles and authors are escaped before <mark> is added, so stored markup is never rendered.
+	mock.ExpectQuery(`ts_rank(.+)ts_headline\('english', replace\(replace\(replace\(replace\(replace\(title, '&', '&amp;'\), '<', '&lt;'\)(.+)`+
+		`ts_headline\('english', replace(.+)\(author, '&', '&amp;'\)(.+)ORDER BY rank DESC(.+)LIMIT \$3 OFFSET \$4`).
+		WithArgs("hobit", 20.0, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "price", "stock", "created_at", "updated_at", "rank", "title_highlight", "author_highlight"}).
+			AddRow(1, "The Hobbit", "J.R.R. Tolkien", 15.0, 3, time.Now(), time.Now(), 0.0, "The Hobbit", "J.R.R. Tolkien"))
+
+	repo := NewPostgresBookRepository(db, time.Second)
+	results, total, err := repo.BookSearch(context.Background(), repository.BookSearchFilter{
+		BookFilter: repository.BookFilter{MaxPrice: 20, Limit: 10},
+		Query:      "hobit",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "The Hobbit", results[0].Title)
+		assert.Equal(t, "J.R.R. Tolkien", results[0].AuthorHighlight)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_GetFiltered_Cursor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	createdAt := time.Date(2024, 5, 1, 10, 30, 0, 123456000, time.UTC)
+	cursor := repository.NewBookCursor("price,-created_at", repository.Book{ID: 5, Price: 12.5, CreatedAt: createdAt})
+
+	authorCondition := `\(author ILIKE \$1 OR id IN \(SELECT ba\.book_id FROM book_authors ba JOIN authors a ON a\.id = ba\.author_id WHERE a\.name ILIKE \$1\)\)`
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE ` + authorCondition + `$`).
+		WithArgs("%Tolkien%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`FROM books WHERE `+authorCondition+` AND \(\(price > \$2\) OR \(price = \$2 AND created_at < \$3\) OR \(price = \$2 AND created_at = \$3 AND id > \$4\)\) `+
+		`ORDER BY price ASC, created_at DESC, id ASC LIMIT \$5 OFFSET \$6`).
+		WithArgs("%Tolkien%", "12.5", "2024-05-01T10:30:00.123456Z", "5", 2, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "price", "stock", "created_at", "updated_at"}).
+			AddRow(7, "The Silmarillion", "J.R.R. Tolkien", 20.0, 1, time.Now(), time.Now()))
+
+	repo := NewPostgresBookRepository(db, time.Second)
+	books, total, err := repo.GetFiltered(context.Background(), repository.BookFilter{
+		Author: "Tolkien",
+		Sort:   "price,-created_at",
+		Cursor: cursor,
+		Limit:  2,
+		Offset: 40,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	if assert.Len(t, books, 1) {
+		assert.Equal(t, int64(7), books[0].ID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_GetFiltered_InvalidPagination(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db, time.Second)
+
+	_, _, err = repo.GetFiltered(context.Background(), repository.BookFilter{Sort: "stock", Limit: 10})
+	assert.ErrorIs(t, err, repository.ErrInvalidSort)
+
+	// A cursor only continues the sort it was issued for.
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	_, _, err = repo.GetFiltered(context.Background(), repository.BookFilter{
+		Sort:   "-price",
+		Cursor: repository.NewBookCursor("price", repository.Book{ID: 5, Price: 12.5}),
+		Limit:  10,
+	})
+	assert.ErrorIs(t, err, repository.ErrInvalidCursor)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_GetFiltered_AuthorAndPublisher(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM books WHERE id IN \(SELECT book_id FROM book_authors WHERE author_id = \$1\) AND publisher_id = \$2$`).
+		WithArgs(3, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(`FROM books WHERE id IN \(SELECT book_id FROM book_authors WHERE author_id = \$1\) AND publisher_id = \$2 ORDER BY id ASC LIMIT \$3 OFFSET \$4`).
+		WithArgs(3, 5, 10, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "price", "stock", "created_at", "updated_at"}))
+
+	repo := NewPostgresBookRepository(db, time.Second)
+	books, total, err := repo.GetFiltered(context.Background(), repository.BookFilter{AuthorID: 3, PublisherID: 5, Limit: 10})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, books)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_UpdateBook_InTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	bookRepo := NewPostgresBookRepository(db, time.Second)
+	repo := NewRepository(db, time.Second, bookRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	// The update must run on the transaction's connection so that it rolls back with it.
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`UPDATE books`).ExpectExec().
+		WithArgs("The Hobbit", "J.R.R. Tolkien", 15.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	cerr := repo.WithTransaction(context.Background(), nil, func(txCtx context.Context) utils.CustomError {
+		if err := bookRepo.UpdateBook(txCtx, &repository.Book{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", Price: 15}); err != nil {
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+
+	assert.Nil(t, cerr)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_UpdateStock_NoRowUpdated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db, time.Second)
+
+	// A missing book must be told apart from one without enough stock.
+	mock.ExpectPrepare(`UPDATE books`).ExpectExec().
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM books WHERE id = \$1\)`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	assert.ErrorIs(t, repo.UpdateStock(context.Background(), 1, 2), repository.ErrBookNotFound)
+
+	mock.ExpectPrepare(`UPDATE books`).ExpectExec().
+		WithArgs(-5, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	assert.ErrorIs(t, repo.UpdateStock(context.Background(), 1, -5), repository.ErrInsufficientStock)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresBookRepository_UpdateStock_ProbeFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresBookRepository(db, time.Second)
+
+	// A failing existence probe must come back as an error rather than a panic.
+	mock.ExpectPrepare(`UPDATE books`).ExpectExec().
+		WithArgs(2, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(1).
+		WillReturnError(context.DeadlineExceeded)
+
+	assert.ErrorIs(t, repo.UpdateStock(context.Background(), 1, 2), context.DeadlineExceeded)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,231 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresCartRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresCartRepository creates a new instance of PostgresCartRepository.
+func NewPostgresCartRepository(db *sql.DB, timeout time.Duration) repository.CartRepository {
+	return &PostgresCartRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// GetOrCreateCart returns the ID of the user's cart, creating the cart if the user has none.
+func (r *PostgresCartRepository) GetOrCreateCart(ctx context.Context, userID int64) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.GetOrCreateCart")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO carts (user_id, created_at, updated_at) 
+		      VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		      ON CONFLICT (user_id) DO UPDATE SET updated_at = CURRENT_TIMESTAMP 
+		      RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get or create cart")
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Cart retrieved successfully")
+	return id, nil
+}
+
+// GetCartItems retrieves the items of a cart with the current book details. Items whose book has
+// been deleted are kept and marked with BookDeleted.
+func (r *PostgresCartRepository) GetCartItems(ctx context.Context, cartID int64) ([]*repository.CartItem, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.GetCartItems")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT ci.id, ci.cart_id, ci.book_id, COALESCE(b.title, ''), COALESCE(b.price, 0), COALESCE(b.stock, 0), b.id IS NULL, ci.quantity 
+		      FROM cart_items ci 
+		      LEFT JOIN books b ON b.id = ci.book_id 
+		      WHERE ci.cart_id = $1 
+		      ORDER BY ci.id`
+
+	items, err := r.queryCartItems(ctx, query, cartID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get cart items")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Cart items retrieved successfully")
+	return items, nil
+}
+
+// GetCartItemsForUpdate retrieves the items of a cart and locks them until the surrounding transaction ends.
+func (r *PostgresCartRepository) GetCartItemsForUpdate(ctx context.Context, cartID int64) ([]*repository.CartItem, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.GetCartItemsForUpdate")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT ci.id, ci.cart_id, ci.book_id, COALESCE(b.title, ''), COALESCE(b.price, 0), COALESCE(b.stock, 0), b.id IS NULL, ci.quantity 
+		      FROM cart_items ci 
+		      LEFT JOIN books b ON b.id = ci.book_id 
+		      WHERE ci.cart_id = $1 
+		      ORDER BY ci.id 
+		      FOR UPDATE OF ci`
+
+	items, err := r.queryCartItems(ctx, query, cartID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to lock cart items")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Cart items locked successfully")
+	return items, nil
+}
+
+// AddCartItem adds quantity copies of a book to the cart, on top of any already in it.
+func (r *PostgresCartRepository) AddCartItem(ctx context.Context, cartID, bookID int64, quantity int) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.AddCartItem")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO cart_items (cart_id, book_id, quantity, created_at, updated_at) 
+		      VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) 
+		      ON CONFLICT (cart_id, book_id) 
+		      DO UPDATE SET quantity = cart_items.quantity + EXCLUDED.quantity, updated_at = CURRENT_TIMESTAMP`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, cartID, bookID, quantity); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to add cart item")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cart item added successfully")
+	return nil
+}
+
+// UpdateCartItem sets the quantity of a book already in the cart.
+func (r *PostgresCartRepository) UpdateCartItem(ctx context.Context, cartID, bookID int64, quantity int) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.UpdateCartItem")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE cart_items 
+		      SET quantity = $1, updated_at = CURRENT_TIMESTAMP 
+		      WHERE cart_id = $2 AND book_id = $3`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, quantity, cartID, bookID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update cart item")
+		return err
+	}
+
+	if err := requireAffected(result); err != nil {
+		span.SetStatus(codes.Error, "Cart item not found")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cart item updated successfully")
+	return nil
+}
+
+// RemoveCartItem removes a book from the cart.
+func (r *PostgresCartRepository) RemoveCartItem(ctx context.Context, cartID, bookID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.RemoveCartItem")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM cart_items WHERE cart_id = $1 AND book_id = $2`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, cartID, bookID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to remove cart item")
+		return err
+	}
+
+	if err := requireAffected(result); err != nil {
+		span.SetStatus(codes.Error, "Cart item not found")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cart item removed successfully")
+	return nil
+}
+
+// ClearCart removes every item from the cart.
+func (r *PostgresCartRepository) ClearCart(ctx context.Context, cartID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCartRepository.ClearCart")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM cart_items WHERE cart_id = $1`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, cartID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to clear cart")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Cart cleared successfully")
+	return nil
+}
+
+// queryCartItems runs a cart item query and scans the results.
+func (r *PostgresCartRepository) queryCartItems(ctx context.Context, query string, cartID int64) ([]*repository.CartItem, error) {
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, cartID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []*repository.CartItem
+	for rows.Next() {
+		var item repository.CartItem
+		if err := rows.Scan(&item.ID, &item.CartID, &item.BookID, &item.Title, &item.Price, &item.Stock, &item.BookDeleted, &item.Quantity); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+
+	return items, rows.Err()
+}
+
+// requireAffected returns repository.ErrCartItemNotFound when the statement touched no rows.
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return repository.ErrCartItemNotFound
+	}
+	return nil
+}
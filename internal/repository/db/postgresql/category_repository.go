@@ -0,0 +1,354 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// Postgres error codes for constraint violations that are reported as domain errors.
+const (
+	pqForeignKeyViolation pq.ErrorCode = "23503"
+	pqUniqueViolation     pq.ErrorCode = "23505"
+)
+
+type PostgresCategoryRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresCategoryRepository creates a new instance of PostgresCategoryRepository.
+func NewPostgresCategoryRepository(db *sql.DB, timeout time.Duration) repository.CategoryRepository {
+	return &PostgresCategoryRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// CreateCategory inserts a new category and returns its ID. It returns
+// repository.ErrCategorySlugTaken when the slug is already in use.
+func (r *PostgresCategoryRepository) CreateCategory(ctx context.Context, category *repository.Category) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.CreateCategory")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO categories (parent_id, name, slug, created_at, updated_at)
+		      VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, category.ParentID, category.Name, category.Slug)
+	if err != nil {
+		if hasPQCode(err, pqUniqueViolation) {
+			span.SetStatus(codes.Error, "Category slug already exists")
+			return 0, repository.ErrCategorySlugTaken
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create category")
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Category created successfully")
+	return id, nil
+}
+
+// GetCategoryByID retrieves a category by its ID.
+func (r *PostgresCategoryRepository) GetCategoryByID(ctx context.Context, categoryID int64) (*repository.Category, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.GetCategoryByID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, parent_id, name, slug, created_at, updated_at
+		      FROM categories
+		      WHERE id = $1`
+
+	category, err := scanCategory(r.db.QueryRowContext(ctx, query, categoryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Category not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get category by ID")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Category retrieved successfully")
+	return category, nil
+}
+
+// GetCategoryBySlug retrieves a category by its slug.
+func (r *PostgresCategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*repository.Category, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.GetCategoryBySlug")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, parent_id, name, slug, created_at, updated_at
+		      FROM categories
+		      WHERE slug = $1`
+
+	category, err := scanCategory(r.db.QueryRowContext(ctx, query, slug))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Category not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get category by slug")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Category retrieved successfully")
+	return category, nil
+}
+
+// GetCategories retrieves every category, ordered by name.
+func (r *PostgresCategoryRepository) GetCategories(ctx context.Context) ([]repository.Category, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.GetCategories")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, parent_id, name, slug, created_at, updated_at
+		      FROM categories
+		      ORDER BY name, id`
+
+	categories, err := r.queryCategories(ctx, query)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get categories")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Categories retrieved successfully")
+	return categories, nil
+}
+
+// UpdateCategory updates a category's parent, name and slug. It returns
+// repository.ErrCategorySlugTaken when the slug is already in use by another category.
+func (r *PostgresCategoryRepository) UpdateCategory(ctx context.Context, category *repository.Category) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.UpdateCategory")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE categories
+		      SET parent_id = $1, name = $2, slug = $3, updated_at = CURRENT_TIMESTAMP
+		      WHERE id = $4`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, category.ParentID, category.Name, category.Slug, category.ID); err != nil {
+		if hasPQCode(err, pqUniqueViolation) {
+			span.SetStatus(codes.Error, "Category slug already exists")
+			return repository.ErrCategorySlugTaken
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update category")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Category updated successfully")
+	return nil
+}
+
+// DeleteCategory removes a category and its book assignments. It returns
+// repository.ErrCategoryHasChildren when the category still has subcategories.
+func (r *PostgresCategoryRepository) DeleteCategory(ctx context.Context, categoryID int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.DeleteCategory")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM categories WHERE id = $1`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, categoryID); err != nil {
+		if hasPQCode(err, pqForeignKeyViolation) {
+			span.SetStatus(codes.Error, "Category has subcategories")
+			return repository.ErrCategoryHasChildren
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to delete category")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Category deleted successfully")
+	return nil
+}
+
+// SetBookCategories replaces the categories a book is assigned to. Run it in a transaction so
+// that the book is never left without its categories. It returns repository.ErrCategoryNotFound
+// when one of the categories does not exist.
+func (r *PostgresCategoryRepository) SetBookCategories(ctx context.Context, bookID int64, categoryIDs []int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.SetBookCategories")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, `DELETE FROM book_categories WHERE book_id = $1`, bookID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to clear book categories")
+		return err
+	}
+
+	if len(categoryIDs) > 0 {
+		query := `INSERT INTO book_categories (book_id, category_id)
+			      SELECT $1, unnest($2::int[])
+			      ON CONFLICT DO NOTHING`
+
+		if _, err := utils.PrepareAndExecContext(ctx, r.db, query, bookID, pq.Array(categoryIDs)); err != nil {
+			if hasPQCode(err, pqForeignKeyViolation) {
+				span.SetStatus(codes.Error, "Category not found")
+				return repository.ErrCategoryNotFound
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to set book categories")
+			return err
+		}
+	}
+
+	span.SetStatus(codes.Ok, "Book categories set successfully")
+	return nil
+}
+
+// GetBookCategories retrieves the categories a book is assigned to, ordered by name.
+func (r *PostgresCategoryRepository) GetBookCategories(ctx context.Context, bookID int64) ([]repository.Category, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.GetBookCategories")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT c.id, c.parent_id, c.name, c.slug, c.created_at, c.updated_at
+		      FROM categories c
+		      JOIN book_categories bc ON bc.category_id = c.id
+		      WHERE bc.book_id = $1
+		      ORDER BY c.name, c.id`
+
+	categories, err := r.queryCategories(ctx, query, bookID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get book categories")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Book categories retrieved successfully")
+	return categories, nil
+}
+
+// CountBooksByCategory counts the books matching filter in each category. A book counts towards
+// its own categories and all of their ancestors, once per category. Categories without matching
+// books are left out; the rest are ordered by descending count.
+func (r *PostgresCategoryRepository) CountBooksByCategory(ctx context.Context, filter repository.BookFilter) ([]repository.CategoryCount, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresCategoryRepository.CountBooksByCategory")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	conditions, params := bookFilterConditions(filter, nil)
+	books := `SELECT id FROM books`
+	if len(conditions) > 0 {
+		books += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// ancestry pairs every category with itself and each of its descendants. UNION rather than
+	// UNION ALL stops the recursion even if the tree ever contains a cycle.
+	query := `WITH RECURSIVE ancestry AS (
+		          SELECT id AS category_id, id AS descendant_id FROM categories
+		          UNION
+		          SELECT a.category_id, c.id FROM categories c JOIN ancestry a ON c.parent_id = a.descendant_id
+		      )
+		      SELECT c.slug, COUNT(DISTINCT bc.book_id) AS count
+		      FROM ancestry a
+		      JOIN categories c ON c.id = a.category_id
+		      JOIN book_categories bc ON bc.category_id = a.descendant_id
+		      WHERE bc.book_id IN (` + books + `)
+		      GROUP BY c.slug
+		      ORDER BY count DESC, c.slug`
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to count books by category")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counts []repository.CategoryCount
+	for rows.Next() {
+		var count repository.CategoryCount
+		if err := rows.Scan(&count.Slug, &count.Count); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		counts = append(counts, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Books counted by category successfully")
+	return counts, nil
+}
+
+// queryCategories runs a category query, on the transaction in ctx if any, and scans the results.
+func (r *PostgresCategoryRepository) queryCategories(ctx context.Context, query string, args ...interface{}) ([]repository.Category, error) {
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []repository.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, *category)
+	}
+
+	return categories, rows.Err()
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanCategory scans a row of id, parent_id, name, slug, created_at and updated_at.
+func scanCategory(row rowScanner) (*repository.Category, error) {
+	var category repository.Category
+	var parentID sql.NullInt64
+	if err := row.Scan(&category.ID, &parentID, &category.Name, &category.Slug, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		category.ParentID = &parentID.Int64
+	}
+	return &category, nil
+}
+
+// hasPQCode reports whether err was caused by a Postgres error with the given code.
+func hasPQCode(err error, code pq.ErrorCode) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == code
+}
@@ -0,0 +1,58 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+func TestPostgresCategoryRepository_CountBooksByCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery(`WITH RECURSIVE ancestry AS (.+) WHERE bc.book_id IN \(SELECT id FROM books WHERE price <= \$1 AND `+
+		`id IN \(SELECT book_id FROM book_categories WHERE category_id IN \(WITH RECURSIVE subtree AS \(\s+SELECT id FROM categories WHERE slug = \$2`).
+		WithArgs(20.0, "fiction").
+		WillReturnRows(sqlmock.NewRows([]string{"slug", "count"}).AddRow("fiction", 3).AddRow("fantasy", 2))
+
+	repo := NewPostgresCategoryRepository(db, time.Second)
+	counts, err := repo.CountBooksByCategory(context.Background(), repository.BookFilter{MaxPrice: 20, Category: "fiction"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []repository.CategoryCount{{Slug: "fiction", Count: 3}, {Slug: "fantasy", Count: 2}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresCategoryRepository_ConstraintErrors(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresCategoryRepository(db, time.Second)
+
+	mock.ExpectPrepare(`DELETE FROM categories`).ExpectExec().
+		WithArgs(1).
+		WillReturnError(&pq.Error{Code: pqForeignKeyViolation})
+	assert.ErrorIs(t, repo.DeleteCategory(context.Background(), 1), repository.ErrCategoryHasChildren)
+
+	mock.ExpectPrepare(`DELETE FROM book_categories`).ExpectExec().
+		WithArgs(7).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`INSERT INTO book_categories`).ExpectExec().
+		WithArgs(7, pq.Array([]int64{1, 99})).
+		WillReturnError(&pq.Error{Code: pqForeignKeyViolation})
+	assert.ErrorIs(t, repo.SetBookCategories(context.Background(), 7, []int64{1, 99}), repository.ErrCategoryNotFound)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
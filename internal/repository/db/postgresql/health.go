@@ -0,0 +1,15 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/masatrio/bookstore-api/internal/domain/health"
+)
+
+// NewHealthChecker checks that the database accepts connections.
+func NewHealthChecker(db *sql.DB) health.HealthChecker {
+	return health.HealthCheckerFunc(func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	})
+}
@@ -0,0 +1,138 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresIdempotencyRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+	ttl     time.Duration
+	lease   time.Duration
+}
+
+// NewPostgresIdempotencyRepository creates a new instance of PostgresIdempotencyRepository.
+// Keys older than ttl are treated as expired and may be reused. A reservation whose request
+// has not completed within lease is taken to be abandoned, for example by a crashed server,
+// and may be reserved again.
+func NewPostgresIdempotencyRepository(db *sql.DB, timeout, ttl, lease time.Duration) repository.IdempotencyRepository {
+	return &PostgresIdempotencyRepository{
+		db:      db,
+		timeout: timeout,
+		ttl:     ttl,
+		lease:   lease,
+	}
+}
+
+// Reserve claims a key for a new request. It returns false when the key is already held by
+// an unexpired record, or by a request still in progress within its lease.
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, userID int64, key, requestHash string) (bool, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresIdempotencyRepository.Reserve")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO idempotency_keys (user_id, key, request_hash, status_code, created_at) 
+		      VALUES ($1, $2, $3, 0, CURRENT_TIMESTAMP) 
+		      ON CONFLICT (user_id, key) DO UPDATE 
+		      SET request_hash = EXCLUDED.request_hash, status_code = 0, response_body = NULL, created_at = CURRENT_TIMESTAMP 
+		      WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - make_interval(secs => $4)
+		         OR (idempotency_keys.status_code = 0 AND idempotency_keys.created_at < CURRENT_TIMESTAMP - make_interval(secs => $5))`
+
+	// The cutoffs are computed from the database clock, which also wrote created_at, so that a
+	// skewed or differently zoned application clock cannot shorten the TTL or the lease.
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, userID, key, requestHash, r.ttl.Seconds(), r.lease.Seconds())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to reserve idempotency key")
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to reserve idempotency key")
+		return false, err
+	}
+
+	span.SetStatus(codes.Ok, "Idempotency key reserve attempted")
+	return affected == 1, nil
+}
+
+// Get retrieves an unexpired idempotency record.
+func (r *PostgresIdempotencyRepository) Get(ctx context.Context, userID int64, key string) (*repository.IdempotencyRecord, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresIdempotencyRepository.Get")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT user_id, key, request_hash, status_code, response_body, created_at 
+		      FROM idempotency_keys 
+		      WHERE user_id = $1 AND key = $2 AND created_at >= CURRENT_TIMESTAMP - make_interval(secs => $3)`
+
+	var record repository.IdempotencyRecord
+	err := r.db.QueryRowContext(ctx, query, userID, key, r.ttl.Seconds()).Scan(
+		&record.UserID, &record.Key, &record.RequestHash, &record.StatusCode, &record.ResponseBody, &record.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Idempotency key not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get idempotency key")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Idempotency key retrieved successfully")
+	return &record, nil
+}
+
+// Complete stores the response produced for a reserved key.
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresIdempotencyRepository.Complete")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE idempotency_keys SET status_code = $1, response_body = $2 WHERE user_id = $3 AND key = $4`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, statusCode, body, userID, key); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to complete idempotency key")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Idempotency key completed successfully")
+	return nil
+}
+
+// Release deletes a reserved key so that the request can be retried.
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, userID int64, key string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresIdempotencyRepository.Release")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, userID, key); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to release idempotency key")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Idempotency key released successfully")
+	return nil
+}
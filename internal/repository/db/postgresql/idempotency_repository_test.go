@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgresIdempotencyRepository_Reserve_ReclaimsAbandonedReservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresIdempotencyRepository(db, time.Second, 24*time.Hour, 10*time.Second)
+
+	// An in-progress reservation older than the lease can be taken over by a retry.
+	// Both cutoffs are taken from the database clock.
+	mock.ExpectPrepare(`ON CONFLICT \(user_id, key\) DO UPDATE (.+) WHERE idempotency_keys.created_at < CURRENT_TIMESTAMP - make_interval\(secs => \$4\)\s+`+
+		`OR \(idempotency_keys.status_code = 0 AND idempotency_keys.created_at < CURRENT_TIMESTAMP - make_interval\(secs => \$5\)\)`).ExpectExec().
+		WithArgs(1, "key-1", "hash", 86400.0, 10.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	reserved, err := repo.Reserve(context.Background(), 1, "key-1", "hash")
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	// A reservation still within its lease keeps the key.
+	mock.ExpectPrepare(`INSERT INTO idempotency_keys`).ExpectExec().
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reserved, err = repo.Reserve(context.Background(), 1, "key-1", "hash")
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPostgresIdempotencyRepository_Get_UsesDatabaseClock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewPostgresIdempotencyRepository(db, time.Second, 24*time.Hour, 10*time.Second)
+
+	mock.ExpectQuery(`FROM idempotency_keys\s+WHERE user_id = \$1 AND key = \$2 AND created_at >= CURRENT_TIMESTAMP - make_interval\(secs => \$3\)`).
+		WithArgs(1, "key-1", 86400.0).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "key", "request_hash", "status_code", "response_body", "created_at"}).
+			AddRow(1, "key-1", "hash", 201, []byte(`{}`), time.Now()))
+
+	record, err := repo.Get(context.Background(), 1, "key-1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, record) {
+		assert.Equal(t, 201, record.StatusCode)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
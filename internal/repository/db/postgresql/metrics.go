@@ -0,0 +1,59 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const meterName = "github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+
+// registerPoolMetrics exports the connection pool statistics of db as gauges and counters that
+// are read on every collection.
+func registerPoolMetrics(db *sql.DB) error {
+	meter := otel.Meter(meterName)
+
+	maxOpen, err := meter.Int64ObservableGauge("db.client.connections.max",
+		metric.WithDescription("Maximum number of open connections allowed."))
+	if err != nil {
+		return err
+	}
+	usage, err := meter.Int64ObservableGauge("db.client.connections.usage",
+		metric.WithDescription("Number of open connections by state."))
+	if err != nil {
+		return err
+	}
+	waits, err := meter.Int64ObservableCounter("db.client.connections.waits",
+		metric.WithDescription("Number of times a connection had to be waited for."))
+	if err != nil {
+		return err
+	}
+	waitDuration, err := meter.Float64ObservableCounter("db.client.connections.wait_duration",
+		metric.WithDescription("Total time spent waiting for a connection."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+	closed, err := meter.Int64ObservableCounter("db.client.connections.closed",
+		metric.WithDescription("Number of connections closed by the pool, by reason."))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := db.Stats()
+		o.ObserveInt64(maxOpen, int64(stats.MaxOpenConnections))
+		o.ObserveInt64(usage, int64(stats.InUse), metric.WithAttributes(attribute.String("state", "used")))
+		o.ObserveInt64(usage, int64(stats.Idle), metric.WithAttributes(attribute.String("state", "idle")))
+		o.ObserveInt64(waits, stats.WaitCount)
+		o.ObserveFloat64(waitDuration, stats.WaitDuration.Seconds())
+		o.ObserveInt64(closed, stats.MaxIdleClosed, metric.WithAttributes(attribute.String("reason", "max_idle")))
+		o.ObserveInt64(closed, stats.MaxIdleTimeClosed, metric.WithAttributes(attribute.String("reason", "max_idle_time")))
+		o.ObserveInt64(closed, stats.MaxLifetimeClosed, metric.WithAttributes(attribute.String("reason", "max_lifetime")))
+		return nil
+	}, maxOpen, usage, waits, waitDuration, closed)
+	return err
+}
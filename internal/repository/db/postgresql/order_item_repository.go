@@ -3,7 +3,9 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/lib/pq"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
@@ -12,13 +14,15 @@ import (
 )
 
 type PostgresOrderItemRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
 // NewPostgresOrderItemRepository creates a new instance of PostgresOrderItemRepository.
-func NewPostgresOrderItemRepository(db *sql.DB) repository.OrderItemRepository {
+func NewPostgresOrderItemRepository(db *sql.DB, timeout time.Duration) repository.OrderItemRepository {
 	return &PostgresOrderItemRepository{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
@@ -27,10 +31,14 @@ func (r *PostgresOrderItemRepository) CreateOrderItem(ctx context.Context, order
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderItemRepository.CreateOrderItem")
 	defer span.End()
 
-	query := `INSERT INTO order_items (order_id, book_id, quantity) 
-		      VALUES ($1, $2, $3) RETURNING id`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
 
-	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, orderItem.OrderID, orderItem.BookID, orderItem.Quantity)
+	query := `INSERT INTO order_items (order_id, book_id, title, quantity, unit_price) 
+		      VALUES ($1, $2, $3, $4, $5) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query,
+		orderItem.OrderID, orderItem.BookID, orderItem.Title, orderItem.Quantity, orderItem.UnitPrice)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to create order item")
@@ -46,7 +54,10 @@ func (r *PostgresOrderItemRepository) GetOrderItemsByOrderID(ctx context.Context
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderItemRepository.GetOrderItemsByOrderID")
 	defer span.End()
 
-	query := `SELECT id, order_id, book_id, quantity 
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, order_id, book_id, title, quantity, unit_price 
 		      FROM order_items 
 		      WHERE order_id = $1`
 
@@ -61,7 +72,7 @@ func (r *PostgresOrderItemRepository) GetOrderItemsByOrderID(ctx context.Context
 	var orderItems []*repository.OrderItem
 	for rows.Next() {
 		var orderItem repository.OrderItem
-		err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.BookID, &orderItem.Quantity)
+		err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.BookID, &orderItem.Title, &orderItem.Quantity, &orderItem.UnitPrice)
 		if err != nil {
 			span.RecordError(err)
 			return nil, err
@@ -77,3 +88,49 @@ func (r *PostgresOrderItemRepository) GetOrderItemsByOrderID(ctx context.Context
 	span.SetStatus(codes.Ok, "Order items retrieved successfully")
 	return orderItems, nil
 }
+
+// GetOrderItemsByOrderIDs retrieves the items of several orders in a single query, grouped by order ID.
+func (r *PostgresOrderItemRepository) GetOrderItemsByOrderIDs(ctx context.Context, orderIDs []int64) (map[int64][]*repository.OrderItem, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderItemRepository.GetOrderItemsByOrderIDs")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	orderItems := make(map[int64][]*repository.OrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		span.SetStatus(codes.Ok, "No orders requested")
+		return orderItems, nil
+	}
+
+	query := `SELECT id, order_id, book_id, title, quantity, unit_price 
+		      FROM order_items 
+		      WHERE order_id = ANY($1) 
+		      ORDER BY order_id, id`
+
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, pq.Array(orderIDs))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get order items by order IDs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var orderItem repository.OrderItem
+		err := rows.Scan(&orderItem.ID, &orderItem.OrderID, &orderItem.BookID, &orderItem.Title, &orderItem.Quantity, &orderItem.UnitPrice)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		orderItems[orderItem.OrderID] = append(orderItems[orderItem.OrderID], &orderItem)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Order items retrieved successfully")
+	return orderItems, nil
+}
@@ -3,6 +3,9 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -12,13 +15,15 @@ import (
 )
 
 type PostgresOrderRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
 // NewPostgresOrderRepository creates a new instance of PostgresOrderRepository.
-func NewPostgresOrderRepository(db *sql.DB) repository.OrderRepository {
+func NewPostgresOrderRepository(db *sql.DB, timeout time.Duration) repository.OrderRepository {
 	return &PostgresOrderRepository{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
@@ -27,10 +32,13 @@ func (r *PostgresOrderRepository) CreateOrder(ctx context.Context, order *reposi
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderRepository.CreateOrder")
 	defer span.End()
 
-	query := `INSERT INTO orders (user_id, status, created_at, updated_at) 
-		      VALUES ($1, $2, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
 
-	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, order.UserID, order.Status)
+	query := `INSERT INTO orders (user_id, status, subtotal, total, created_at, updated_at) 
+		      VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, order.UserID, order.Status, order.Subtotal, order.Total)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to create order")
@@ -46,14 +54,17 @@ func (r *PostgresOrderRepository) GetOrderByID(ctx context.Context, orderID int6
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderRepository.GetOrderByID")
 	defer span.End()
 
-	query := `SELECT id, user_id, status, created_at, updated_at 
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, user_id, status, subtotal, total, created_at, updated_at 
 		      FROM orders 
 		      WHERE id = $1`
 
 	row := utils.PrepareAndQueryRowContext(ctx, r.db, query, orderID)
 
 	var order repository.Order
-	err := row.Scan(&order.ID, &order.UserID, &order.Status, &order.CreatedAt, &order.UpdatedAt)
+	err := row.Scan(&order.ID, &order.UserID, &order.Status, &order.Subtotal, &order.Total, &order.CreatedAt, &order.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			span.SetStatus(codes.Ok, "Order not found")
@@ -68,18 +79,49 @@ func (r *PostgresOrderRepository) GetOrderByID(ctx context.Context, orderID int6
 	return &order, nil
 }
 
-// GetOrdersByUserID retrieves orders by user ID with pagination.
-func (r *PostgresOrderRepository) GetOrdersByUserID(ctx context.Context, userID int64, limit, offset int) ([]*repository.Order, error) {
+// GetOrdersByUserID retrieves a user's orders, ordered by filter.Sort. When a cursor is given,
+// the page starts after the order it was issued for instead of at the offset.
+func (r *PostgresOrderRepository) GetOrdersByUserID(ctx context.Context, userID int64, filter repository.OrderFilter) ([]*repository.Order, error) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderRepository.GetOrdersByUserID")
 	defer span.End()
 
-	query := `SELECT id, user_id, status, created_at, updated_at 
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	if filter.Sort == "" {
+		filter.Sort = repository.DefaultOrderSort
+	}
+	fields, err := repository.ParseSort(filter.Sort, repository.OrderSortFields)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid sort")
+		return nil, err
+	}
+	keys := sortKeys(fields)
+
+	conditions := []string{"user_id = $1"}
+	params := []interface{}{userID}
+
+	offset := filter.Offset
+	if filter.Cursor != "" {
+		var keyset string
+		keyset, params, err = keysetCondition(keys, filter.Cursor, filter.Sort, params)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Invalid cursor")
+			return nil, err
+		}
+		conditions = append(conditions, keyset)
+		offset = 0
+	}
+
+	query := `SELECT id, user_id, status, subtotal, total, created_at, updated_at 
               FROM orders 
-              WHERE user_id = $1
-              ORDER BY created_at DESC
-              LIMIT $2 OFFSET $3`
+              WHERE ` + strings.Join(conditions, " AND ") + orderByClause(keys) +
+		fmt.Sprintf(" LIMIT $%d OFFSET $%d", len(params)+1, len(params)+2)
+	params = append(params, filter.Limit, offset)
 
-	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, userID, limit, offset)
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, params...)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to get orders by user ID")
@@ -90,7 +132,7 @@ func (r *PostgresOrderRepository) GetOrdersByUserID(ctx context.Context, userID
 	var orders []*repository.Order
 	for rows.Next() {
 		var order repository.Order
-		err := rows.Scan(&order.ID, &order.UserID, &order.Status, &order.CreatedAt, &order.UpdatedAt)
+		err := rows.Scan(&order.ID, &order.UserID, &order.Status, &order.Subtotal, &order.Total, &order.CreatedAt, &order.UpdatedAt)
 		if err != nil {
 			span.RecordError(err)
 			return nil, err
@@ -106,3 +148,38 @@ func (r *PostgresOrderRepository) GetOrdersByUserID(ctx context.Context, userID
 	span.SetStatus(codes.Ok, "Orders retrieved successfully")
 	return orders, nil
 }
+
+// UpdateOrderStatus moves an order from fromStatus to toStatus. It returns
+// repository.ErrOrderStatusConflict when the order is no longer in fromStatus.
+func (r *PostgresOrderRepository) UpdateOrderStatus(ctx context.Context, orderID int64, fromStatus, toStatus string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderRepository.UpdateOrderStatus")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE orders 
+		      SET status = $1, updated_at = CURRENT_TIMESTAMP 
+		      WHERE id = $2 AND status = $3`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, toStatus, orderID, fromStatus)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update order status")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update order status")
+		return err
+	}
+	if affected == 0 {
+		span.SetStatus(codes.Error, "Order status conflict")
+		return repository.ErrOrderStatusConflict
+	}
+
+	span.SetStatus(codes.Ok, "Order status updated successfully")
+	return nil
+}
@@ -0,0 +1,90 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresOrderStatusHistoryRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresOrderStatusHistoryRepository creates a new instance of PostgresOrderStatusHistoryRepository.
+func NewPostgresOrderStatusHistoryRepository(db *sql.DB, timeout time.Duration) repository.OrderStatusHistoryRepository {
+	return &PostgresOrderStatusHistoryRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// CreateStatusHistory records a status transition and returns the inserted entry's ID.
+func (r *PostgresOrderStatusHistoryRepository) CreateStatusHistory(ctx context.Context, history *repository.OrderStatusHistory) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderStatusHistoryRepository.CreateStatusHistory")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO order_status_history (order_id, from_status, to_status, changed_by, note, created_at) 
+		      VALUES ($1, NULLIF($2, ''), $3, NULLIF($4, 0), $5, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query,
+		history.OrderID, history.FromStatus, history.ToStatus, history.ChangedBy, history.Note)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create order status history")
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Order status history created successfully")
+	return id, nil
+}
+
+// GetStatusHistoryByOrderID retrieves the status transitions of an order, oldest first.
+func (r *PostgresOrderStatusHistoryRepository) GetStatusHistoryByOrderID(ctx context.Context, orderID int64) ([]*repository.OrderStatusHistory, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresOrderStatusHistoryRepository.GetStatusHistoryByOrderID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, order_id, COALESCE(from_status, ''), to_status, COALESCE(changed_by, 0), note, created_at 
+		      FROM order_status_history 
+		      WHERE order_id = $1 
+		      ORDER BY created_at, id`
+
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, orderID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get order status history")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []*repository.OrderStatusHistory
+	for rows.Next() {
+		var entry repository.OrderStatusHistory
+		err := rows.Scan(&entry.ID, &entry.OrderID, &entry.FromStatus, &entry.ToStatus, &entry.ChangedBy, &entry.Note, &entry.CreatedAt)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+		history = append(history, &entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Order status history retrieved successfully")
+	return history, nil
+}
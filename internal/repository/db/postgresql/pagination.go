@@ -0,0 +1,68 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+)
+
+// sortKeys returns the columns a listing is ordered by: the requested fields followed by id,
+// so that rows with equal sort values still have a stable order. Fields are validated against
+// a whitelist before they get here, so their names are safe to use as column names.
+func sortKeys(fields []repository.SortField) []repository.SortField {
+	for _, field := range fields {
+		if field.Name == "id" {
+			return fields
+		}
+	}
+	return append(fields[:len(fields):len(fields)], repository.SortField{Name: "id"})
+}
+
+// orderByClause returns the ORDER BY clause for the given sort keys.
+func orderByClause(keys []repository.SortField) string {
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = key.Name + " ASC"
+		if key.Desc {
+			columns[i] = key.Name + " DESC"
+		}
+	}
+	return " ORDER BY " + strings.Join(columns, ", ")
+}
+
+// keysetCondition returns a condition selecting the rows that come after the cursor row in the
+// order given by keys, numbering its placeholders after params. Because keys may mix sort
+// directions, the row comparison is expanded into
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ..., with < for descending keys.
+func keysetCondition(keys []repository.SortField, cursor, sort string, params []interface{}) (string, []interface{}, error) {
+	values, err := repository.DecodeCursor(cursor, sort)
+	if err != nil {
+		return "", nil, err
+	}
+	// Cursors hold one value per requested field followed by the row's ID.
+	if len(values) < len(keys) || len(values) > len(keys)+1 {
+		return "", nil, repository.ErrInvalidCursor
+	}
+
+	placeholders := make([]string, len(keys))
+	for i := range keys {
+		params = append(params, values[i])
+		placeholders[i] = fmt.Sprintf("$%d", len(params))
+	}
+
+	alternatives := make([]string, len(keys))
+	for i, key := range keys {
+		terms := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			terms = append(terms, keys[j].Name+" = "+placeholders[j])
+		}
+		operator := " > "
+		if key.Desc {
+			operator = " < "
+		}
+		terms = append(terms, key.Name+operator+placeholders[i])
+		alternatives[i] = "(" + strings.Join(terms, " AND ") + ")"
+	}
+	return "(" + strings.Join(alternatives, " OR ") + ")", params, nil
+}
@@ -8,7 +8,8 @@ import (
 	"github.com/masatrio/bookstore-api/config"
 )
 
-// NewDatabase initializes a new database connection and returns it.
+// NewDatabase initializes a new database connection and returns it. The pool statistics are
+// exported through the global meter provider.
 func NewDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
 	db, err := sql.Open("postgres", cfg.URL)
 	if err != nil {
@@ -19,5 +20,10 @@ func NewDatabase(cfg config.DatabaseConfig) (*sql.DB, error) {
 	db.SetMaxOpenConns(cfg.MaxActiveConnection)
 	db.SetConnMaxIdleTime(time.Duration(cfg.MaxIdleTime) * time.Second)
 
+	if err := registerPoolMetrics(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	return db, nil
 }
@@ -0,0 +1,137 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresPublisherRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresPublisherRepository creates a new instance of PostgresPublisherRepository.
+func NewPostgresPublisherRepository(db *sql.DB, timeout time.Duration) repository.PublisherRepository {
+	return &PostgresPublisherRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// GetPublisherByID retrieves a publisher by its ID.
+func (r *PostgresPublisherRepository) GetPublisherByID(ctx context.Context, publisherID int64) (*repository.Publisher, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresPublisherRepository.GetPublisherByID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, name, created_at, updated_at
+		      FROM publishers
+		      WHERE id = $1`
+
+	publisher := &repository.Publisher{}
+	err := r.db.QueryRowContext(ctx, query, publisherID).Scan(&publisher.ID, &publisher.Name, &publisher.CreatedAt, &publisher.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Publisher not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get publisher by ID")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Publisher retrieved successfully")
+	return publisher, nil
+}
+
+// GetOrCreatePublisher returns the publisher with the given name, creating it when no
+// publisher's name matches it. Names match regardless of case, spacing and punctuation.
+func (r *PostgresPublisherRepository) GetOrCreatePublisher(ctx context.Context, name string) (*repository.Publisher, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresPublisherRepository.GetOrCreatePublisher")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	// The no-op update makes RETURNING yield the existing row on a conflict.
+	query := `INSERT INTO publishers (name, created_at, updated_at)
+		      VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+		      ON CONFLICT (name_key) DO UPDATE SET name = publishers.name
+		      RETURNING id, name, created_at, updated_at`
+
+	publisher := &repository.Publisher{}
+	err := utils.QueryRowContext(ctx, r.db, query, name).Scan(&publisher.ID, &publisher.Name, &publisher.CreatedAt, &publisher.UpdatedAt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get or create publisher")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Publisher retrieved successfully")
+	return publisher, nil
+}
+
+// SetBookPublisher assigns a book to a publisher, or removes its publisher when publisherID is
+// nil. It returns repository.ErrPublisherNotFound when the publisher does not exist.
+func (r *PostgresPublisherRepository) SetBookPublisher(ctx context.Context, bookID int64, publisherID *int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresPublisherRepository.SetBookPublisher")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE books
+		      SET publisher_id = $1, updated_at = CURRENT_TIMESTAMP
+		      WHERE id = $2`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, publisherID, bookID); err != nil {
+		if hasPQCode(err, pqForeignKeyViolation) {
+			span.SetStatus(codes.Error, "Publisher not found")
+			return repository.ErrPublisherNotFound
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to set book publisher")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Book publisher set successfully")
+	return nil
+}
+
+// GetBookPublisher retrieves the publisher of a book. It returns nil when the book has none.
+func (r *PostgresPublisherRepository) GetBookPublisher(ctx context.Context, bookID int64) (*repository.Publisher, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresPublisherRepository.GetBookPublisher")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT p.id, p.name, p.created_at, p.updated_at
+		      FROM publishers p
+		      JOIN books b ON b.publisher_id = p.id
+		      WHERE b.id = $1`
+
+	publisher := &repository.Publisher{}
+	err := r.db.QueryRowContext(ctx, query, bookID).Scan(&publisher.ID, &publisher.Name, &publisher.CreatedAt, &publisher.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "Book has no publisher")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get book publisher")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Book publisher retrieved successfully")
+	return publisher, nil
+}
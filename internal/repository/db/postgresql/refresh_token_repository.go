@@ -0,0 +1,196 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresRefreshTokenRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresRefreshTokenRepository creates a new instance of PostgresRefreshTokenRepository.
+func NewPostgresRefreshTokenRepository(db *sql.DB, timeout time.Duration) repository.RefreshTokenRepository {
+	return &PostgresRefreshTokenRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// Create inserts a new refresh token and returns its ID.
+func (r *PostgresRefreshTokenRepository) Create(ctx context.Context, token *repository.RefreshToken) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresRefreshTokenRepository.Create")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO refresh_tokens (user_id, token_hash, family_id, access_token_id, access_expires_at, expires_at, created_at) 
+		      VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query,
+		token.UserID, token.TokenHash, token.FamilyID, token.AccessTokenID, token.AccessExpiresAt, token.ExpiresAt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create refresh token")
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "Refresh token created successfully")
+	return id, nil
+}
+
+// GetByHash retrieves a refresh token by the hash of its value.
+func (r *PostgresRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*repository.RefreshToken, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresRefreshTokenRepository.GetByHash")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, user_id, token_hash, family_id, access_token_id, access_expires_at, expires_at, rotated_at, revoked_at, created_at 
+		      FROM refresh_tokens WHERE token_hash = $1`
+
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, tokenHash)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get refresh token")
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens, err := scanRefreshTokens(rows)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to scan refresh token")
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		span.SetStatus(codes.Ok, "Refresh token not found")
+		return nil, nil
+	}
+
+	span.SetStatus(codes.Ok, "Refresh token retrieved successfully")
+	return tokens[0], nil
+}
+
+// Rotate marks a refresh token as exchanged. It returns repository.ErrRefreshTokenRotated if the
+// token was already rotated or revoked, which happens when the same token is used twice.
+func (r *PostgresRefreshTokenRepository) Rotate(ctx context.Context, id int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresRefreshTokenRepository.Rotate")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE refresh_tokens SET rotated_at = CURRENT_TIMESTAMP 
+		      WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to rotate refresh token")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to rotate refresh token")
+		return err
+	}
+	if affected == 0 {
+		span.SetStatus(codes.Error, "Refresh token already rotated")
+		return repository.ErrRefreshTokenRotated
+	}
+
+	span.SetStatus(codes.Ok, "Refresh token rotated successfully")
+	return nil
+}
+
+// RevokeFamily revokes every live token in a family and returns the tokens it revoked.
+func (r *PostgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) ([]*repository.RefreshToken, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresRefreshTokenRepository.RevokeFamily")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP 
+		      WHERE family_id = $1 AND revoked_at IS NULL 
+		      RETURNING id, user_id, token_hash, family_id, access_token_id, access_expires_at, expires_at, rotated_at, revoked_at, created_at`
+
+	tokens, err := r.revoke(ctx, query, familyID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to revoke refresh token family")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "Refresh token family revoked successfully")
+	return tokens, nil
+}
+
+// RevokeByUserID revokes every live token of a user and returns the tokens it revoked.
+func (r *PostgresRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID int64) ([]*repository.RefreshToken, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresRefreshTokenRepository.RevokeByUserID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP 
+		      WHERE user_id = $1 AND revoked_at IS NULL 
+		      RETURNING id, user_id, token_hash, family_id, access_token_id, access_expires_at, expires_at, rotated_at, revoked_at, created_at`
+
+	tokens, err := r.revoke(ctx, query, userID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to revoke user refresh tokens")
+		return nil, err
+	}
+
+	span.SetStatus(codes.Ok, "User refresh tokens revoked successfully")
+	return tokens, nil
+}
+
+// revoke runs a revoking UPDATE ... RETURNING query and scans the affected tokens.
+func (r *PostgresRefreshTokenRepository) revoke(ctx context.Context, query string, arg interface{}) ([]*repository.RefreshToken, error) {
+	rows, err := utils.PrepareAndQueryContext(ctx, r.db, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanRefreshTokens(rows)
+}
+
+// scanRefreshTokens scans refresh token rows.
+func scanRefreshTokens(rows *sql.Rows) ([]*repository.RefreshToken, error) {
+	var tokens []*repository.RefreshToken
+	for rows.Next() {
+		var token repository.RefreshToken
+		var rotatedAt, revokedAt sql.NullTime
+		if err := rows.Scan(&token.ID, &token.UserID, &token.TokenHash, &token.FamilyID, &token.AccessTokenID,
+			&token.AccessExpiresAt, &token.ExpiresAt, &rotatedAt, &revokedAt, &token.CreatedAt); err != nil {
+			return nil, err
+		}
+		if rotatedAt.Valid {
+			token.RotatedAt = &rotatedAt.Time
+		}
+		if revokedAt.Valid {
+			token.RevokedAt = &revokedAt.Time
+		}
+		tokens = append(tokens, &token)
+	}
+
+	return tokens, rows.Err()
+}
@@ -3,10 +3,13 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/masatrio/bookstore-api/internal/domain/repository"
 	"github.com/masatrio/bookstore-api/utils"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -15,24 +18,48 @@ type RepositoryImpl struct {
 	bookRepo      repository.BookRepository
 	orderRepo     repository.OrderRepository
 	orderItemRepo repository.OrderItemRepository
+	historyRepo   repository.OrderStatusHistoryRepository
 	userRepo      repository.UserRepository
+	cartRepo      repository.CartRepository
+	categoryRepo  repository.CategoryRepository
+	authorRepo    repository.AuthorRepository
+	publisherRepo repository.PublisherRepository
+	refreshRepo   repository.RefreshTokenRepository
+	userTokenRepo repository.UserTokenRepository
 	db            *sql.DB
+	timeout       time.Duration
 }
 
-// NewRepository creates a new instance of RepositoryImpl.
+// NewRepository creates a new instance of RepositoryImpl. Transactions are bounded by timeout.
 func NewRepository(
 	db *sql.DB,
+	timeout time.Duration,
 	bookRepo repository.BookRepository,
 	orderRepo repository.OrderRepository,
 	orderItemRepo repository.OrderItemRepository,
+	historyRepo repository.OrderStatusHistoryRepository,
 	userRepo repository.UserRepository,
+	cartRepo repository.CartRepository,
+	categoryRepo repository.CategoryRepository,
+	authorRepo repository.AuthorRepository,
+	publisherRepo repository.PublisherRepository,
+	refreshRepo repository.RefreshTokenRepository,
+	userTokenRepo repository.UserTokenRepository,
 ) repository.Repository {
 	return &RepositoryImpl{
 		bookRepo:      bookRepo,
 		orderRepo:     orderRepo,
 		orderItemRepo: orderItemRepo,
+		historyRepo:   historyRepo,
 		userRepo:      userRepo,
+		cartRepo:      cartRepo,
+		categoryRepo:  categoryRepo,
+		authorRepo:    authorRepo,
+		publisherRepo: publisherRepo,
+		refreshRepo:   refreshRepo,
+		userTokenRepo: userTokenRepo,
 		db:            db,
+		timeout:       timeout,
 	}
 }
 
@@ -51,50 +78,168 @@ func (r *RepositoryImpl) OrderItemRepository() repository.OrderItemRepository {
 	return r.orderItemRepo
 }
 
+// OrderStatusHistoryRepository returns the OrderStatusHistoryRepository instance.
+func (r *RepositoryImpl) OrderStatusHistoryRepository() repository.OrderStatusHistoryRepository {
+	return r.historyRepo
+}
+
 // UserRepository returns the UserRepository instance.
 func (r *RepositoryImpl) UserRepository() repository.UserRepository {
 	return r.userRepo
 }
 
-// WithTransaction wraps the database operation in a transaction.
-func (r *RepositoryImpl) WithTransaction(fn repository.TransactionFunc) utils.CustomError {
-	ctx, span := trace.SpanFromContext(context.Background()).TracerProvider().Tracer("").Start(context.Background(), "PostgresUserRepository.WithTransaction")
+// CartRepository returns the CartRepository instance.
+func (r *RepositoryImpl) CartRepository() repository.CartRepository {
+	return r.cartRepo
+}
+
+// CategoryRepository returns the CategoryRepository instance.
+func (r *RepositoryImpl) CategoryRepository() repository.CategoryRepository {
+	return r.categoryRepo
+}
+
+// AuthorRepository returns the AuthorRepository instance.
+func (r *RepositoryImpl) AuthorRepository() repository.AuthorRepository {
+	return r.authorRepo
+}
+
+// PublisherRepository returns the PublisherRepository instance.
+func (r *RepositoryImpl) PublisherRepository() repository.PublisherRepository {
+	return r.publisherRepo
+}
+
+// RefreshTokenRepository returns the RefreshTokenRepository instance.
+func (r *RepositoryImpl) RefreshTokenRepository() repository.RefreshTokenRepository {
+	return r.refreshRepo
+}
+
+// UserTokenRepository returns the UserTokenRepository instance.
+func (r *RepositoryImpl) UserTokenRepository() repository.UserTokenRepository {
+	return r.userTokenRepo
+}
+
+// WithTransaction runs fn in a transaction that inherits the caller's context. opts selects the
+// isolation level and read-only mode; nil uses the database defaults. The transaction is
+// bounded by the database timeout, and database/sql rolls it back when the context ends before
+// it commits.
+//
+// When ctx already carries a transaction, fn runs inside a savepoint of it and opts is ignored.
+// A top-level transaction that fails with a serialization failure or deadlock is retried with
+// backoff, so fn must be safe to run more than once.
+func (r *RepositoryImpl) WithTransaction(ctx context.Context, opts *sql.TxOptions, fn repository.TransactionFunc) utils.CustomError {
+	if _, ok := utils.TransactionFromContext(ctx); ok {
+		return r.withSavepoint(ctx, fn)
+	}
+
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RepositoryImpl.WithTransaction")
 	defer span.End()
 
-	tx, err := r.db.Begin()
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	for attempt := 1; ; attempt++ {
+		err := r.runTransaction(ctx, opts, fn)
+		if err == nil {
+			span.SetStatus(codes.Ok, "Transaction committed successfully")
+			return nil
+		}
+
+		code, retryable := retryableTransactionError(err)
+		if !retryable || attempt == maxTransactionAttempts {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Transaction failed")
+			return err
+		}
+
+		delay := transactionRetryDelay(attempt)
+		span.AddEvent("transaction_retry", trace.WithAttributes(
+			attribute.Int("attempt", attempt),
+			attribute.String("pg_code", code),
+			attribute.Int64("delay_ms", delay.Milliseconds()),
+		))
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			span.RecordError(ctx.Err())
+			span.SetStatus(codes.Error, "Transaction retry abandoned")
+			return utils.NewCustomDatabaseError(ctx.Err())
+		}
+	}
+}
+
+// runTransaction makes a single attempt at running fn in a new transaction.
+func (r *RepositoryImpl) runTransaction(ctx context.Context, opts *sql.TxOptions, fn repository.TransactionFunc) utils.CustomError {
+	span := trace.SpanFromContext(ctx)
+
+	tx, err := r.db.BeginTx(ctx, opts)
 	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to begin transaction")
-		return utils.NewCustomSystemError(err.Error())
+		return utils.NewCustomDatabaseError(err)
 	}
 
-	ctx = context.WithValue(ctx, utils.TransactionContextKey, tx)
-
+	// If fn panics, roll back before the panic carries on up the stack, so that the row locks it
+	// took are not held until the request context is cancelled.
 	defer func() {
-		if err != nil {
-			if rollbackErr := tx.Rollback(); rollbackErr != nil {
-				err = fmt.Errorf("rollback failed: %v; original error: %w", rollbackErr, err)
+		if p := recover(); p != nil {
+			if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+				span.RecordError(fmt.Errorf("rollback failed: %w", err))
 			}
-			span.RecordError(err)
-			span.SetStatus(codes.Error, "Transaction rolled back")
+			panic(p)
 		}
 	}()
 
-	funcErr := fn(ctx)
-	if funcErr != nil {
-		err = funcErr
+	txCtx := utils.ContextWithTransaction(ctx, tx)
+	if funcErr := fn(txCtx); funcErr != nil {
+		if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+			span.RecordError(fmt.Errorf("rollback failed: %w", err))
+		}
+		return funcErr
+	}
+
+	if err := tx.Commit(); err != nil {
+		// A transaction whose context expired is already rolled back and reports ErrTxDone.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return utils.NewCustomDatabaseError(ctxErr)
+		}
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	// Cache invalidations wait for the commit so concurrent reads cannot cache pre-commit rows.
+	utils.RunCommitHooks(txCtx, ctx)
+	return nil
+}
+
+// withSavepoint runs fn inside a savepoint of the transaction in ctx, rolling back to the
+// savepoint if fn fails so that the enclosing transaction can carry on.
+func (r *RepositoryImpl) withSavepoint(ctx context.Context, fn repository.TransactionFunc) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "RepositoryImpl.WithSavepoint")
+	defer span.End()
+
+	tx, _ := utils.TransactionFromContext(ctx)
+	ctx, name := utils.ContextWithSavepoint(ctx)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Transaction function error")
+		span.SetStatus(codes.Error, "Failed to create savepoint")
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	if funcErr := fn(ctx); funcErr != nil {
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+			span.RecordError(fmt.Errorf("rollback to savepoint failed: %w", err))
+		}
+		span.RecordError(funcErr)
+		span.SetStatus(codes.Error, "Rolled back to savepoint")
 		return funcErr
 	}
 
-	err = tx.Commit()
-	if err != nil {
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to commit transaction")
-		return utils.NewCustomSystemError(err.Error())
+		span.SetStatus(codes.Error, "Failed to release savepoint")
+		return utils.NewCustomDatabaseError(err)
 	}
 
-	span.SetStatus(codes.Ok, "Transaction committed successfully")
+	span.SetStatus(codes.Ok, "Savepoint released")
 	return nil
 }
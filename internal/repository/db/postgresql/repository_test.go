@@ -0,0 +1,157 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+func newTestRepository(t *testing.T) (*RepositoryImpl, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return NewRepository(db, time.Second, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil).(*RepositoryImpl), mock
+}
+
+func TestWithTransaction_RetriesSerializationFailure(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE books").ExpectExec().WillReturnError(&pq.Error{Code: "40001"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectPrepare("UPDATE books").ExpectExec().WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	attempts := 0
+	err := repo.WithTransaction(context.Background(), &sql.TxOptions{Isolation: sql.LevelSerializable}, func(ctx context.Context) utils.CustomError {
+		attempts++
+		if _, err := utils.PrepareAndExecContext(ctx, nil, "UPDATE books SET stock = stock - 1"); err != nil {
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTransaction_GivesUpAfterMaxAttempts(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	for i := 0; i < maxTransactionAttempts; i++ {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	attempts := 0
+	err := repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+		attempts++
+		return utils.NewCustomDatabaseError(&pq.Error{Code: "40P01"})
+	})
+
+	assert.NotNil(t, err)
+	assert.True(t, err.IsSystemError())
+	assert.Equal(t, maxTransactionAttempts, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTransaction_DoesNotRetryOtherErrors(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	attempts := 0
+	err := repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+		attempts++
+		return utils.NewCustomUserError("Insufficient stock")
+	})
+
+	assert.NotNil(t, err)
+	assert.True(t, err.IsUserError())
+	assert.Equal(t, 1, attempts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTransaction_NestedUsesSavepoints(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	err := repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+		nestedErr := repo.WithTransaction(ctx, nil, func(ctx context.Context) utils.CustomError {
+			return utils.NewCustomConflictError("already applied")
+		})
+		assert.True(t, nestedErr.IsConflictError())
+
+		return repo.WithTransaction(ctx, nil, func(ctx context.Context) utils.CustomError {
+			return nil
+		})
+	})
+
+	assert.Nil(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTransaction_RunsCommitHooksAfterCommit(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	committed := false
+	err := repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+		utils.AfterCommit(ctx, func(context.Context) { committed = true })
+		assert.False(t, committed, "hook ran before the commit")
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, committed)
+
+	// A rolled back transaction drops its hooks.
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	ran := false
+	err = repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+		utils.AfterCommit(ctx, func(context.Context) { ran = true })
+		return utils.NewCustomUserError("Insufficient stock")
+	})
+
+	assert.NotNil(t, err)
+	assert.False(t, ran)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithTransaction_RollsBackOnPanic(t *testing.T) {
+	repo, mock := newTestRepository(t)
+
+	// A panic in fn must not leave the transaction, and its locks, open.
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = repo.WithTransaction(context.Background(), nil, func(ctx context.Context) utils.CustomError {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
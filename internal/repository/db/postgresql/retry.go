@@ -0,0 +1,43 @@
+package postgresql
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// maxTransactionAttempts is how many times a transaction runs before a serialization failure
+	// or deadlock is returned to the caller.
+	maxTransactionAttempts = 3
+
+	transactionRetryBaseDelay = 20 * time.Millisecond
+)
+
+// Postgres error codes that mean the transaction lost a race and can safely be run again.
+const (
+	pqSerializationFailure pq.ErrorCode = "40001"
+	pqDeadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// retryableTransactionError reports whether err was caused by a serialization failure or a
+// deadlock, returning the Postgres error code.
+func retryableTransactionError(err error) (string, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return "", false
+	}
+	if pqErr.Code != pqSerializationFailure && pqErr.Code != pqDeadlockDetected {
+		return "", false
+	}
+	return string(pqErr.Code), true
+}
+
+// transactionRetryDelay returns the exponential backoff before the given retry, with jitter so
+// that the transactions that collided do not collide again.
+func transactionRetryDelay(attempt int) time.Duration {
+	backoff := transactionRetryBaseDelay << (attempt - 1)
+	return backoff + rand.N(transactionRetryBaseDelay)
+}
@@ -0,0 +1,16 @@
+package postgresql
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout bounds a single repository call by the configured database timeout so that a
+// slow query is cancelled instead of outliving the request. An earlier deadline already set
+// on ctx still wins, and a non-positive timeout leaves ctx unchanged.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -3,6 +3,7 @@ package postgresql
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -12,13 +13,15 @@ import (
 )
 
 type PostgresUserRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	timeout time.Duration
 }
 
 // NewPostgresUserRepository creates a new instance of PostgresUserRepository.
-func NewPostgresUserRepository(db *sql.DB) repository.UserRepository {
+func NewPostgresUserRepository(db *sql.DB, timeout time.Duration) repository.UserRepository {
 	return &PostgresUserRepository{
-		db: db,
+		db:      db,
+		timeout: timeout,
 	}
 }
 
@@ -27,10 +30,13 @@ func (r *PostgresUserRepository) Create(ctx context.Context, user *repository.Us
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.Create")
 	defer span.End()
 
-	query := `INSERT INTO users (name, email, password, created_at, updated_at) 
-		      VALUES ($1, $2, $3, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
 
-	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, user.Name, user.Email, user.Password)
+	query := `INSERT INTO users (name, email, password, role, created_at, updated_at) 
+		      VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, user.Name, user.Email, user.Password, user.Role)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Failed to create user")
@@ -46,11 +52,15 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id int64) (*reposi
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.GetByID")
 	defer span.End()
 
-	query := `SELECT id, name, email, password, created_at, updated_at FROM users WHERE id = $1`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, name, email, password, role, email_verified_at, created_at, updated_at FROM users WHERE id = $1`
 
 	user := &repository.User{}
+	var emailVerifiedAt sql.NullTime
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &emailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			span.SetStatus(codes.Ok, "User not found")
@@ -61,6 +71,10 @@ func (r *PostgresUserRepository) GetByID(ctx context.Context, id int64) (*reposi
 		return nil, err
 	}
 
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+
 	span.SetStatus(codes.Ok, "User retrieved successfully")
 	return user, nil
 }
@@ -70,11 +84,15 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.GetByEmail")
 	defer span.End()
 
-	query := `SELECT id, name, email, password, created_at, updated_at FROM users WHERE email = $1`
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, name, email, password, role, email_verified_at, created_at, updated_at FROM users WHERE email = $1`
 
 	user := &repository.User{}
+	var emailVerifiedAt sql.NullTime
 	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Password, &user.CreatedAt, &user.UpdatedAt)
+		&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &emailVerifiedAt, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			span.SetStatus(codes.Ok, "User not found")
@@ -85,6 +103,72 @@ func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (
 		return nil, err
 	}
 
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+
 	span.SetStatus(codes.Ok, "User retrieved successfully")
 	return user, nil
 }
+
+// UpdateRole sets the role of the user with the given ID.
+func (r *PostgresUserRepository) UpdateRole(ctx context.Context, id int64, role string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.UpdateRole")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	_, err := r.db.ExecContext(ctx, query, role, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update user role")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "User role updated successfully")
+	return nil
+}
+
+// UpdatePassword replaces the password hash of the user with the given ID.
+func (r *PostgresUserRepository) UpdatePassword(ctx context.Context, id int64, password string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.UpdatePassword")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE users SET password = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, password, id); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update user password")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "User password updated successfully")
+	return nil
+}
+
+// MarkEmailVerified records that the user with the given ID has confirmed their email address.
+func (r *PostgresUserRepository) MarkEmailVerified(ctx context.Context, id int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserRepository.MarkEmailVerified")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE users SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP 
+		      WHERE id = $1`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, id); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to mark email verified")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "Email marked verified successfully")
+	return nil
+}
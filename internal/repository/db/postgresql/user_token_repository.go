@@ -0,0 +1,134 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type PostgresUserTokenRepository struct {
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// NewPostgresUserTokenRepository creates a new instance of PostgresUserTokenRepository.
+func NewPostgresUserTokenRepository(db *sql.DB, timeout time.Duration) repository.UserTokenRepository {
+	return &PostgresUserTokenRepository{
+		db:      db,
+		timeout: timeout,
+	}
+}
+
+// Create inserts a new user token and returns its ID.
+func (r *PostgresUserTokenRepository) Create(ctx context.Context, token *repository.UserToken) (int64, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserTokenRepository.Create")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at, created_at) 
+		      VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP) RETURNING id`
+
+	id, err := utils.ExecContextWithPreparedReturningID(ctx, r.db, query, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to create user token")
+		return 0, err
+	}
+
+	span.SetStatus(codes.Ok, "User token created successfully")
+	return id, nil
+}
+
+// GetByHash retrieves a user token by its purpose and the hash of its value.
+func (r *PostgresUserTokenRepository) GetByHash(ctx context.Context, purpose, tokenHash string) (*repository.UserToken, error) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserTokenRepository.GetByHash")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at 
+		      FROM user_tokens WHERE purpose = $1 AND token_hash = $2`
+
+	var token repository.UserToken
+	var usedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query, purpose, tokenHash).Scan(
+		&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &token.ExpiresAt, &usedAt, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			span.SetStatus(codes.Ok, "User token not found")
+			return nil, nil
+		}
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to get user token")
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	span.SetStatus(codes.Ok, "User token retrieved successfully")
+	return &token, nil
+}
+
+// MarkUsed consumes a token. It returns repository.ErrUserTokenUsed if the token was already used.
+func (r *PostgresUserTokenRepository) MarkUsed(ctx context.Context, id int64) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserTokenRepository.MarkUsed")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP WHERE id = $1 AND used_at IS NULL`
+
+	result, err := utils.PrepareAndExecContext(ctx, r.db, query, id)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to mark user token used")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to mark user token used")
+		return err
+	}
+	if affected == 0 {
+		span.SetStatus(codes.Error, "User token already used")
+		return repository.ErrUserTokenUsed
+	}
+
+	span.SetStatus(codes.Ok, "User token marked used successfully")
+	return nil
+}
+
+// InvalidateByUserID consumes every outstanding token of a user for the given purpose, so that
+// only the most recently issued token works.
+func (r *PostgresUserTokenRepository) InvalidateByUserID(ctx context.Context, userID int64, purpose string) error {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "PostgresUserTokenRepository.InvalidateByUserID")
+	defer span.End()
+
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+
+	query := `UPDATE user_tokens SET used_at = CURRENT_TIMESTAMP 
+		      WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`
+
+	if _, err := utils.PrepareAndExecContext(ctx, r.db, query, userID, purpose); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to invalidate user tokens")
+		return err
+	}
+
+	span.SetStatus(codes.Ok, "User tokens invalidated successfully")
+	return nil
+}
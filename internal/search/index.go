@@ -0,0 +1,381 @@
+package search
+
+import (
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	domainsearch "github.com/masatrio/bookstore-api/internal/domain/search"
+)
+
+// Title terms count for more than author terms when scoring.
+const (
+	titleWeight  = 2.0
+	authorWeight = 1.0
+)
+
+// maxAuthorFacets caps the author facet to its most common values.
+const maxAuthorFacets = 10
+
+// priceBuckets are the upper bounds of the price facet ranges. Prices at or above the last
+// bound fall into an open-ended range.
+var priceBuckets = []float64{10, 25, 50, 100}
+
+// Index is an embedded in-process inverted index. When it has a path, every change is written
+// to a snapshot there so that the next start can load it instead of reindexing; rewriting the
+// snapshot on each change suits a catalog-sized index.
+type Index struct {
+	mu       sync.RWMutex
+	path     string
+	docs     map[int64]domainsearch.Document
+	postings map[string]map[int64]float64 // term -> document ID -> weighted term frequency
+}
+
+// NewIndex creates an empty index that snapshots to path, or keeps everything in memory when
+// path is empty.
+func NewIndex(path string) *Index {
+	return &Index{
+		path:     path,
+		docs:     make(map[int64]domainsearch.Document),
+		postings: make(map[string]map[int64]float64),
+	}
+}
+
+// Open creates an index that snapshots to path and loads the existing snapshot, if any.
+func Open(path string) (*Index, error) {
+	index := NewIndex(path)
+	if path == "" {
+		return index, nil
+	}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var docs []domainsearch.Document
+	if err := gob.NewDecoder(f).Decode(&docs); err != nil {
+		return nil, fmt.Errorf("decode search index snapshot: %w", err)
+	}
+	for _, doc := range docs {
+		index.add(doc)
+	}
+	return index, nil
+}
+
+// Len returns the number of indexed documents.
+func (i *Index) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return len(i.docs)
+}
+
+// Index adds the document, replacing any earlier version of it.
+func (i *Index) Index(ctx context.Context, doc domainsearch.Document) error {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "Index.Index")
+	defer span.End()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(doc.ID)
+	i.add(doc)
+	return i.save(span)
+}
+
+// Delete removes the document. Deleting an unknown ID is not an error.
+func (i *Index) Delete(ctx context.Context, id int64) error {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "Index.Delete")
+	defer span.End()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+	return i.save(span)
+}
+
+// Replace swaps the whole index for docs.
+func (i *Index) Replace(ctx context.Context, docs []domainsearch.Document) error {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "Index.Replace")
+	defer span.End()
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.docs = make(map[int64]domainsearch.Document, len(docs))
+	i.postings = make(map[string]map[int64]float64)
+	for _, doc := range docs {
+		i.add(doc)
+	}
+	return i.save(span)
+}
+
+// Query returns the documents that contain every term of the query text, scored by TF-IDF with
+// title matches weighted above author matches. An empty text matches every document; a text
+// without any searchable terms matches none.
+func (i *Index) Query(ctx context.Context, query domainsearch.Query) (*domainsearch.Result, error) {
+	_, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "Index.Query")
+	defer span.End()
+
+	if query.Limit < 0 || query.Offset < 0 {
+		err := fmt.Errorf("invalid limit %d or offset %d", query.Limit, query.Offset)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Invalid pagination")
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	matched := make(map[string]bool)
+	for _, term := range terms(query.Text) {
+		matched[term] = true
+	}
+
+	scores := make(map[int64]float64)
+	// Text made only of punctuation, such as "!!!", has no terms and matches nothing rather than
+	// everything.
+	if len(matched) > 0 || strings.TrimSpace(query.Text) == "" {
+		scores = i.score(matched)
+	}
+	hits := make([]domainsearch.Hit, 0, len(scores))
+	var docs []domainsearch.Document
+	for id, score := range scores {
+		doc := i.docs[id]
+		if !matchesFilters(doc, query) {
+			continue
+		}
+		hits = append(hits, domainsearch.Hit{ID: id, Score: score})
+		docs = append(docs, doc)
+	}
+
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].Score != hits[b].Score {
+			return hits[a].Score > hits[b].Score
+		}
+		return hits[a].ID < hits[b].ID
+	})
+
+	result := &domainsearch.Result{
+		Total:  len(hits),
+		Facets: countFacets(docs, query.Facets),
+	}
+
+	start := min(query.Offset, len(hits))
+	end := min(start+query.Limit, len(hits))
+	result.Hits = hits[start:end]
+	for n := range result.Hits {
+		doc := i.docs[result.Hits[n].ID]
+		result.Hits[n].TitleHighlight = highlight(doc.Title, matched)
+		result.Hits[n].AuthorHighlight = highlight(doc.Author, matched)
+	}
+
+	span.SetStatus(codes.Ok, "Index queried successfully")
+	return result, nil
+}
+
+// score returns the TF-IDF score of every document that contains all of the terms. With no
+// terms every document matches with a zero score.
+func (i *Index) score(terms map[string]bool) map[int64]float64 {
+	scores := make(map[int64]float64)
+	if len(terms) == 0 {
+		for id := range i.docs {
+			scores[id] = 0
+		}
+		return scores
+	}
+
+	first := true
+	for term := range terms {
+		postings := i.postings[term]
+		idf := math.Log(1 + float64(len(i.docs))/float64(len(postings)+1))
+
+		next := make(map[int64]float64, len(postings))
+		for id, tf := range postings {
+			if score, ok := scores[id]; ok || first {
+				next[id] = score + tf*idf
+			}
+		}
+		scores, first = next, false
+	}
+	return scores
+}
+
+// add indexes doc. The caller holds the write lock and has removed any earlier version.
+func (i *Index) add(doc domainsearch.Document) {
+	i.docs[doc.ID] = doc
+	for term, tf := range documentTerms(doc) {
+		if i.postings[term] == nil {
+			i.postings[term] = make(map[int64]float64)
+		}
+		i.postings[term][doc.ID] = tf
+	}
+}
+
+// remove drops the document from the index. The caller holds the write lock.
+func (i *Index) remove(id int64) {
+	doc, ok := i.docs[id]
+	if !ok {
+		return
+	}
+	for term := range documentTerms(doc) {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	delete(i.docs, id)
+}
+
+// save writes the snapshot, if the index has a path. The file is replaced atomically so that
+// a crash never leaves a partial snapshot behind. The caller holds the write lock.
+func (i *Index) save(span trace.Span) error {
+	if i.path == "" {
+		return nil
+	}
+
+	docs := make([]domainsearch.Document, 0, len(i.docs))
+	for _, doc := range i.docs {
+		docs = append(docs, doc)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(i.path), filepath.Base(i.path)+".*")
+	if err != nil {
+		span.RecordError(err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := gob.NewEncoder(tmp).Encode(docs); err != nil {
+		tmp.Close()
+		span.RecordError(err)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	if err := os.Rename(tmp.Name(), i.path); err != nil {
+		span.RecordError(err)
+		return err
+	}
+	return nil
+}
+
+// documentTerms returns the weighted term frequencies of doc.
+func documentTerms(doc domainsearch.Document) map[string]float64 {
+	tf := make(map[string]float64)
+	for _, term := range terms(doc.Title) {
+		tf[term] += titleWeight
+	}
+	for _, term := range terms(doc.Author) {
+		tf[term] += authorWeight
+	}
+	return tf
+}
+
+// matchesFilters reports whether doc passes the listing filters of query.
+func matchesFilters(doc domainsearch.Document, query domainsearch.Query) bool {
+	switch {
+	case query.Title != "" && !strings.Contains(strings.ToLower(doc.Title), strings.ToLower(query.Title)):
+		return false
+	case query.Author != "" && !strings.Contains(strings.ToLower(doc.Author), strings.ToLower(query.Author)):
+		return false
+	case query.MinPrice > 0 && doc.Price < query.MinPrice:
+		return false
+	case query.MaxPrice > 0 && doc.Price > query.MaxPrice:
+		return false
+	case !query.StartDate.IsZero() && doc.CreatedAt.Before(query.StartDate):
+		return false
+	case !query.EndDate.IsZero() && doc.CreatedAt.After(query.EndDate):
+		return false
+	}
+	return true
+}
+
+// countFacets counts the requested facets over docs.
+func countFacets(docs []domainsearch.Document, facets []string) map[string][]domainsearch.FacetCount {
+	if len(facets) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]domainsearch.FacetCount, len(facets))
+	for _, facet := range facets {
+		switch facet {
+		case domainsearch.FacetAuthor:
+			result[facet] = authorFacet(docs)
+		case domainsearch.FacetPrice:
+			result[facet] = priceFacet(docs)
+		}
+	}
+	return result
+}
+
+// authorFacet returns the most common authors, most frequent first.
+func authorFacet(docs []domainsearch.Document) []domainsearch.FacetCount {
+	counts := make(map[string]int)
+	for _, doc := range docs {
+		counts[doc.Author]++
+	}
+
+	facet := make([]domainsearch.FacetCount, 0, len(counts))
+	for author, count := range counts {
+		facet = append(facet, domainsearch.FacetCount{Value: author, Count: count})
+	}
+	sort.Slice(facet, func(a, b int) bool {
+		if facet[a].Count != facet[b].Count {
+			return facet[a].Count > facet[b].Count
+		}
+		return facet[a].Value < facet[b].Value
+	})
+
+	if len(facet) > maxAuthorFacets {
+		facet = facet[:maxAuthorFacets]
+	}
+	return facet
+}
+
+// priceFacet counts docs per price range, cheapest first, leaving out empty ranges.
+func priceFacet(docs []domainsearch.Document) []domainsearch.FacetCount {
+	counts := make([]int, len(priceBuckets)+1)
+	for _, doc := range docs {
+		n := sort.Search(len(priceBuckets), func(n int) bool { return doc.Price < priceBuckets[n] })
+		counts[n]++
+	}
+
+	var facet []domainsearch.FacetCount
+	for n, count := range counts {
+		if count > 0 {
+			facet = append(facet, domainsearch.FacetCount{Value: priceLabel(n), Count: count})
+		}
+	}
+	return facet
+}
+
+// priceLabel names the nth price range, such as "10-25" or "100+". Ranges include their lower
+// bound and exclude their upper bound.
+func priceLabel(n int) string {
+	lower := 0.0
+	if n > 0 {
+		lower = priceBuckets[n-1]
+	}
+	if n == len(priceBuckets) {
+		return fmt.Sprintf("%g+", lower)
+	}
+	return fmt.Sprintf("%g-%g", lower, priceBuckets[n])
+}
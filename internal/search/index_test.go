@@ -0,0 +1,129 @@
+package search
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domainsearch "github.com/masatrio/bookstore-api/internal/domain/search"
+)
+
+func newTestIndex(t *testing.T, path string) *Index {
+	index := NewIndex(path)
+	err := index.Replace(context.Background(), []domainsearch.Document{
+		{ID: 1, Title: "The Hobbit", Author: "J.R.R. Tolkien", Price: 15},
+		{ID: 2, Title: "The Fellowship of the Ring", Author: "J.R.R. Tolkien", Price: 25},
+		{ID: 3, Title: "Dragons of Autumn Twilight", Author: "Margaret Weis", Price: 9.5},
+		{ID: 4, Title: "Tolkien: A Biography", Author: "Humphrey Carpenter", Price: 120},
+	})
+	assert.NoError(t, err)
+	return index
+}
+
+func TestIndex_Query(t *testing.T) {
+	index := newTestIndex(t, "")
+	ctx := context.Background()
+
+	result, err := index.Query(ctx, domainsearch.Query{Text: "tolkien", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	if assert.Len(t, result.Hits, 3) {
+		// A title match outranks an author match.
+		assert.Equal(t, int64(4), result.Hits[0].ID)
+		assert.Equal(t, "<mark>Tolkien</mark>: A Biography", result.Hits[0].TitleHighlight)
+		assert.Equal(t, "J.R.R. <mark>Tolkien</mark>", result.Hits[1].AuthorHighlight)
+	}
+
+	result, err = index.Query(ctx, domainsearch.Query{Text: "Tolkien's hobbits", Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Hits, 1) {
+		assert.Equal(t, int64(1), result.Hits[0].ID)
+	}
+
+	result, err = index.Query(ctx, domainsearch.Query{Text: "dragon", Limit: 10})
+	assert.NoError(t, err)
+	if assert.Len(t, result.Hits, 1) {
+		assert.Equal(t, "<mark>Dragons</mark> of Autumn Twilight", result.Hits[0].TitleHighlight)
+	}
+
+	result, err = index.Query(ctx, domainsearch.Query{Text: "tolkien", MaxPrice: 20, Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+
+	result, err = index.Query(ctx, domainsearch.Query{Text: "tolkien", Limit: 1, Offset: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.Total)
+	assert.Len(t, result.Hits, 1)
+
+	_, err = index.Query(ctx, domainsearch.Query{Text: "tolkien", Limit: -1})
+	assert.Error(t, err)
+
+	// Punctuation alone yields no terms, which must not match every book.
+	result, err = index.Query(ctx, domainsearch.Query{Text: "!!!", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.Total)
+	assert.Empty(t, result.Hits)
+}
+
+func TestIndex_Facets(t *testing.T) {
+	index := newTestIndex(t, "")
+
+	result, err := index.Query(context.Background(), domainsearch.Query{
+		Facets: []string{domainsearch.FacetAuthor, domainsearch.FacetPrice},
+		Limit:  10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Total)
+	assert.Equal(t, []domainsearch.FacetCount{
+		{Value: "J.R.R. Tolkien", Count: 2},
+		{Value: "Humphrey Carpenter", Count: 1},
+		{Value: "Margaret Weis", Count: 1},
+	}, result.Facets[domainsearch.FacetAuthor])
+	assert.Equal(t, []domainsearch.FacetCount{
+		{Value: "0-10", Count: 1},
+		{Value: "10-25", Count: 1},
+		{Value: "25-50", Count: 1},
+		{Value: "100+", Count: 1},
+	}, result.Facets[domainsearch.FacetPrice])
+}
+
+func TestIndex_IndexAndDelete(t *testing.T) {
+	index := newTestIndex(t, "")
+	ctx := context.Background()
+
+	assert.NoError(t, index.Index(ctx, domainsearch.Document{ID: 1, Title: "There and Back Again", Author: "J.R.R. Tolkien"}))
+	result, err := index.Query(ctx, domainsearch.Query{Text: "hobbit", Limit: 10})
+	assert.NoError(t, err)
+	assert.Zero(t, result.Total)
+
+	assert.NoError(t, index.Delete(ctx, 2))
+	result, err = index.Query(ctx, domainsearch.Query{Text: "tolkien", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, 3, index.Len())
+}
+
+func TestOpen_LoadsSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "search.gob")
+	newTestIndex(t, path)
+
+	index, err := Open(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, index.Len())
+
+	result, err := index.Query(context.Background(), domainsearch.Query{Text: "hobbit", Limit: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Total)
+
+	empty, err := Open(filepath.Join(t.TempDir(), "missing.gob"))
+	assert.NoError(t, err)
+	assert.Zero(t, empty.Len())
+}
+
+func TestHighlight_EscapesText(t *testing.T) {
+	// Markup stored in a title is escaped; only the <mark> tags are left as HTML.
+	got := highlight(`Dragons & <script>alert("x")</script> Dungeons`, map[string]bool{"dragon": true, "script": true})
+	assert.Equal(t, `<mark>Dragons</mark> &amp; &lt;<mark>script</mark>&gt;alert(&#34;x&#34;)&lt;/<mark>script</mark>&gt; Dungeons`, got)
+}
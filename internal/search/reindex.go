@@ -0,0 +1,34 @@
+package search
+
+import (
+	"context"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	domainsearch "github.com/masatrio/bookstore-api/internal/domain/search"
+)
+
+// reindexPageSize is how many books Reindex reads per query.
+const reindexPageSize = 500
+
+// Reindex replaces the contents of index with every book in books and returns how many were
+// indexed.
+func Reindex(ctx context.Context, books repository.BookRepository, index *Index) (int, error) {
+	var docs []domainsearch.Document
+	for offset := 0; ; offset += reindexPageSize {
+		page, _, err := books.GetFiltered(ctx, repository.BookFilter{Limit: reindexPageSize, Offset: offset})
+		if err != nil {
+			return 0, err
+		}
+		for _, book := range page {
+			docs = append(docs, domainsearch.NewDocument(book))
+		}
+		if len(page) < reindexPageSize {
+			break
+		}
+	}
+
+	if err := index.Replace(ctx, docs); err != nil {
+		return 0, err
+	}
+	return len(docs), nil
+}
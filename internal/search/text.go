@@ -0,0 +1,80 @@
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// span is the byte range of a word in a text.
+type span struct {
+	start, end int
+}
+
+// words returns the byte ranges of the words in text. Letters, digits and apostrophes make up
+// a word.
+func words(text string) []span {
+	var spans []span
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '\'' || r == '’'
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, span{start, len(text)})
+	}
+	return spans
+}
+
+// terms returns the normalized terms of text, in order and including repeats.
+func terms(text string) []string {
+	var result []string
+	for _, s := range words(text) {
+		if term := normalize(text[s.start:s.end]); term != "" {
+			result = append(result, term)
+		}
+	}
+	return result
+}
+
+// normalize lowercases a word and strips possessives and regular plurals so that "Tolkien's"
+// matches "tolkien" and "dragons" matches "dragon". It is deliberately simpler than a full
+// stemmer.
+func normalize(word string) string {
+	term := strings.ToLower(strings.Trim(word, "'’"))
+	term = strings.TrimSuffix(strings.TrimSuffix(term, "'s"), "’s")
+
+	switch {
+	case len(term) > 4 && strings.HasSuffix(term, "ies"):
+		term = term[:len(term)-3] + "y"
+	case len(term) > 3 && strings.HasSuffix(term, "s") &&
+		!strings.HasSuffix(term, "ss") && !strings.HasSuffix(term, "us") && !strings.HasSuffix(term, "is"):
+		term = term[:len(term)-1]
+	}
+	return term
+}
+
+// highlight wraps the words of text whose normalized form is in matched in <mark> tags. The
+// text itself is HTML-escaped, so that the tags are the only markup in the result.
+func highlight(text string, matched map[string]bool) string {
+	var b strings.Builder
+	last := 0
+	for _, s := range words(text) {
+		if !matched[normalize(text[s.start:s.end])] {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:s.start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[s.start:s.end]))
+		b.WriteString("</mark>")
+		last = s.end
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
@@ -0,0 +1,283 @@
+package book
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// creditSeparator joins the authors of a credit line; creditSplitter also accepts ";" so that
+// credit lines written either way are split into their authors.
+const creditSeparator = " & "
+
+var creditSplitter = regexp.MustCompile(`\s*[&;]\s*`)
+
+// SetBookAuthors replaces the contributors credited on a book and rewrites its credit line from
+// the names of those with the author role.
+func (b *bookUseCase) SetBookAuthors(ctx context.Context, id int64, input usecase.SetBookAuthorsInput) (*usecase.Book, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.SetBookAuthors")
+	defer span.End()
+
+	if cerr := validateContributors(input.Authors); cerr != nil {
+		return nil, cerr
+	}
+
+	book, err := b.repo.BookRepository().GetBookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return nil, utils.NewCustomNotFoundError("Book ID Not Found")
+	}
+
+	cerr := b.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		contributors := make([]repository.BookAuthor, 0, len(input.Authors))
+		credited := make(map[int64]bool)
+		var credit []string
+		for _, contributor := range input.Authors {
+			author, err := b.repo.AuthorRepository().GetOrCreateAuthor(txCtx, strings.TrimSpace(contributor.Name))
+			if err != nil {
+				span.RecordError(err)
+				return utils.NewCustomDatabaseError(err)
+			}
+
+			role := contributorRole(contributor)
+			contributors = append(contributors, repository.BookAuthor{
+				BookID:   id,
+				AuthorID: author.ID,
+				Name:     author.Name,
+				Role:     role,
+			})
+			if role == repository.RoleAuthor && !credited[author.ID] {
+				credited[author.ID] = true
+				credit = append(credit, author.Name)
+			}
+		}
+
+		if err := b.repo.AuthorRepository().SetBookAuthors(txCtx, id, contributors); err != nil {
+			if errors.Is(err, repository.ErrAuthorNotFound) {
+				return utils.NewCustomNotFoundError("Author Not Found")
+			}
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		book.Author = strings.Join(credit, creditSeparator)
+		if err := b.repo.BookRepository().UpdateBook(txCtx, book); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	b.indexBook(ctx, *book)
+
+	return b.GetBook(ctx, id)
+}
+
+// ListAuthorBooks lists the books an author is credited on in any role, with the same filters,
+// sorting and pagination as ListBooks.
+func (b *bookUseCase) ListAuthorBooks(ctx context.Context, authorID int64, input usecase.ListBooksInput) (*usecase.AuthorBooksOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.ListAuthorBooks")
+	defer span.End()
+
+	author, err := b.repo.AuthorRepository().GetAuthorByID(ctx, authorID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if author == nil {
+		return nil, utils.NewCustomNotFoundError("Author Not Found")
+	}
+
+	input.AuthorID = authorID
+	output, cerr := b.ListBooks(ctx, input)
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	if err := b.attachAuthors(ctx, output.Books); err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return &usecase.AuthorBooksOutput{
+		Author:          usecase.Author{ID: author.ID, Name: author.Name},
+		ListBooksOutput: *output,
+	}, nil
+}
+
+// ListPublisherBooks lists the books of a publisher, with the same filters, sorting and
+// pagination as ListBooks.
+func (b *bookUseCase) ListPublisherBooks(ctx context.Context, publisherID int64, input usecase.ListBooksInput) (*usecase.PublisherBooksOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.ListPublisherBooks")
+	defer span.End()
+
+	publisher, err := b.repo.PublisherRepository().GetPublisherByID(ctx, publisherID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if publisher == nil {
+		return nil, utils.NewCustomNotFoundError("Publisher Not Found")
+	}
+
+	input.PublisherID = publisherID
+	output, cerr := b.ListBooks(ctx, input)
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	if err := b.attachAuthors(ctx, output.Books); err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return &usecase.PublisherBooksOutput{
+		Publisher:       usecase.Publisher{ID: publisher.ID, Name: publisher.Name},
+		ListBooksOutput: *output,
+	}, nil
+}
+
+// creditAuthors credits a book to the authors named in its credit line, creating the authors
+// that do not exist yet. Contributors in current with other roles stay credited after them.
+func (b *bookUseCase) creditAuthors(ctx context.Context, bookID int64, credit string, current []repository.BookAuthor) error {
+	var contributors []repository.BookAuthor
+	for _, name := range splitCredit(credit) {
+		author, err := b.repo.AuthorRepository().GetOrCreateAuthor(ctx, name)
+		if err != nil {
+			return err
+		}
+		contributors = append(contributors, repository.BookAuthor{
+			BookID:   bookID,
+			AuthorID: author.ID,
+			Name:     author.Name,
+			Role:     repository.RoleAuthor,
+		})
+	}
+
+	for _, contributor := range current {
+		if contributor.Role != repository.RoleAuthor {
+			contributors = append(contributors, contributor)
+		}
+	}
+
+	return b.repo.AuthorRepository().SetBookAuthors(ctx, bookID, contributors)
+}
+
+// setPublisher assigns a book to the publisher with the given name, creating it if needed. An
+// empty name removes the book's publisher.
+func (b *bookUseCase) setPublisher(ctx context.Context, bookID int64, name string) utils.CustomError {
+	span := trace.SpanFromContext(ctx)
+
+	var publisherID *int64
+	if name = strings.TrimSpace(name); name != "" {
+		publisher, err := b.repo.PublisherRepository().GetOrCreatePublisher(ctx, name)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		publisherID = &publisher.ID
+	}
+
+	if err := b.repo.PublisherRepository().SetBookPublisher(ctx, bookID, publisherID); err != nil {
+		if errors.Is(err, repository.ErrPublisherNotFound) {
+			return utils.NewCustomNotFoundError("Publisher Not Found")
+		}
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	return nil
+}
+
+// attachAuthors sets the contributors credited on each of books.
+func (b *bookUseCase) attachAuthors(ctx context.Context, books []usecase.Book) error {
+	if len(books) == 0 {
+		return nil
+	}
+
+	bookIDs := make([]int64, len(books))
+	for i, book := range books {
+		bookIDs[i] = book.ID
+	}
+
+	contributors, err := b.repo.AuthorRepository().GetBookAuthors(ctx, bookIDs)
+	if err != nil {
+		return err
+	}
+
+	byBook := make(map[int64][]usecase.BookAuthor, len(books))
+	for _, contributor := range contributors {
+		byBook[contributor.BookID] = append(byBook[contributor.BookID], usecase.BookAuthor{
+			ID:   contributor.AuthorID,
+			Name: contributor.Name,
+			Role: contributor.Role,
+		})
+	}
+	for i := range books {
+		books[i].Authors = byBook[books[i].ID]
+	}
+	return nil
+}
+
+// validateContributors checks that every contributor has a name and a known role and that at
+// least one of them is credited as an author.
+func validateContributors(contributors []usecase.BookAuthorInput) utils.CustomError {
+	hasAuthor := false
+	for _, contributor := range contributors {
+		if !hasAlnum(contributor.Name) {
+			return utils.NewCustomUserError("Author name must contain a letter or digit")
+		}
+		switch contributorRole(contributor) {
+		case repository.RoleAuthor:
+			hasAuthor = true
+		case repository.RoleTranslator, repository.RoleEditor:
+		default:
+			return utils.NewCustomUserError("Role must be author, translator or editor")
+		}
+	}
+	if !hasAuthor {
+		return utils.NewCustomUserError("At least one author is required")
+	}
+	return nil
+}
+
+// contributorRole returns the contributor's role, defaulting to author.
+func contributorRole(contributor usecase.BookAuthorInput) string {
+	if contributor.Role == "" {
+		return repository.RoleAuthor
+	}
+	return contributor.Role
+}
+
+// splitCredit returns the author names in a credit line such as "Terry Pratchett & Neil Gaiman".
+// Parts without a letter or digit are dropped.
+func splitCredit(credit string) []string {
+	var names []string
+	for _, name := range creditSplitter.Split(credit, -1) {
+		if name = strings.TrimSpace(name); hasAlnum(name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// hasAlnum reports whether s contains a letter or digit, which every author and publisher name
+// needs to tell it apart from others.
+func hasAlnum(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0
+}
@@ -0,0 +1,33 @@
+package book
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+)
+
+func TestSplitCredit(t *testing.T) {
+	assert.Equal(t, []string{"J.R.R. Tolkien"}, splitCredit("J.R.R. Tolkien"))
+	assert.Equal(t, []string{"Terry Pratchett", "Neil Gaiman"}, splitCredit("Terry Pratchett & Neil Gaiman"))
+	assert.Equal(t, []string{"Brian Kernighan", "Dennis Ritchie"}, splitCredit(" Brian Kernighan;Dennis Ritchie "))
+
+	// Separators without a name between them do not produce authors.
+	assert.Equal(t, []string{"Homer"}, splitCredit("Homer & & -"))
+	assert.Nil(t, splitCredit("???"))
+}
+
+func TestValidateContributors(t *testing.T) {
+	assert.Nil(t, validateContributors([]usecase.BookAuthorInput{
+		{Name: "Leo Tolstoy"},
+		{Name: "Richard Pevear", Role: "translator"},
+	}))
+
+	// A book needs someone credited as its author.
+	assert.NotNil(t, validateContributors([]usecase.BookAuthorInput{{Name: "Richard Pevear", Role: "translator"}}))
+	assert.NotNil(t, validateContributors(nil))
+
+	assert.NotNil(t, validateContributors([]usecase.BookAuthorInput{{Name: "Leo Tolstoy", Role: "illustrator"}}))
+	assert.NotNil(t, validateContributors([]usecase.BookAuthorInput{{Name: "..."}}))
+}
@@ -2,23 +2,31 @@ package book
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/search"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase"
 	"github.com/masatrio/bookstore-api/utils"
 )
 
+// categoryFacet is the ListBooksOutput facet that counts books per category slug.
+const categoryFacet = "category"
+
 type bookUseCase struct {
-	repo repository.Repository
+	repo  repository.Repository
+	index search.SearchIndex
 }
 
-// NewBookUseCase creates a new instance of bookUseCase.
-func NewBookUseCase(repo repository.Repository) usecase.BookUseCase {
+// NewBookUseCase creates a new instance of bookUseCase. Searches go to index, which is kept in
+// sync as books change; with a nil index they use the database's full-text search instead.
+func NewBookUseCase(repo repository.Repository, index search.SearchIndex) usecase.BookUseCase {
 	return &bookUseCase{
-		repo: repo,
+		repo:  repo,
+		index: index,
 	}
 }
 
@@ -27,54 +35,255 @@ func (b *bookUseCase) ListBooks(ctx context.Context, input usecase.ListBooksInpu
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.ListBooks")
 	defer span.End()
 
+	if input.Query != "" && (input.Sort != "" || input.Cursor != "") {
+		return nil, utils.NewCustomUserError("sort and cursor cannot be combined with q")
+	}
+	if input.Limit < 0 || input.Offset < 0 {
+		return nil, utils.NewCustomUserError("limit and offset must not be negative")
+	}
+	if _, err := repository.ParseSort(input.Sort, repository.BookSortFields); err != nil {
+		return nil, utils.NewCustomUserError(err.Error())
+	}
+
 	filter := repository.BookFilter{
+		Title:       input.Title,
+		Author:      input.Author,
+		MinPrice:    input.MinPrice,
+		MaxPrice:    input.MaxPrice,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		Category:    input.Category,
+		AuthorID:    input.AuthorID,
+		PublisherID: input.PublisherID,
+		Limit:       input.Limit,
+		Offset:      input.Offset,
+	}
+
+	// The search index only knows the credit line, not categories or the normalized authors and
+	// publishers, so searches filtered by those use the database.
+	indexable := input.Category == "" && input.AuthorID == 0 && input.PublisherID == 0
+	if input.Query != "" && b.index != nil && indexable {
+		return b.queryIndex(ctx, input)
+	}
+	if input.Query != "" {
+		return b.searchBooks(ctx, input, filter)
+	}
+
+	// One extra book tells whether another page follows.
+	filter.Sort = input.Sort
+	filter.Cursor = input.Cursor
+	filter.Limit = input.Limit + 1
+
+	books, totalCount, err := b.repo.BookRepository().GetFiltered(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, utils.NewCustomUserError("Invalid cursor")
+		}
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	var nextCursor string
+	if len(books) > input.Limit {
+		books = books[:input.Limit]
+		if input.Limit > 0 {
+			nextCursor = repository.NewBookCursor(input.Sort, books[input.Limit-1])
+		}
+	}
+
+	categoryCounts, err := b.repo.CategoryRepository().CountBooksByCategory(ctx, filter)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	output := &usecase.ListBooksOutput{
+		Books:      convertToUsecaseBooks(books),
+		TotalCount: totalCount,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
+		NextCursor: nextCursor,
+	}
+	if len(categoryCounts) > 0 {
+		facets := make([]usecase.FacetCount, len(categoryCounts))
+		for i, count := range categoryCounts {
+			facets[i] = usecase.FacetCount{Value: count.Slug, Count: count.Count}
+		}
+		output.Facets = map[string][]usecase.FacetCount{categoryFacet: facets}
+	}
+	return output, nil
+}
+
+// searchBooks runs the full-text query in input, returning the books by relevance with the
+// matched terms highlighted.
+func (b *bookUseCase) searchBooks(ctx context.Context, input usecase.ListBooksInput, filter repository.BookFilter) (*usecase.ListBooksOutput, utils.CustomError) {
+	span := trace.SpanFromContext(ctx)
+
+	results, totalCount, err := b.repo.BookRepository().BookSearch(ctx, repository.BookSearchFilter{
+		BookFilter: filter,
+		Query:      input.Query,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	books := make([]usecase.Book, len(results))
+	for i, result := range results {
+		books[i] = usecase.Book{
+			ID:        result.ID,
+			Title:     result.Title,
+			Author:    result.Author,
+			Price:     result.Price,
+			Stock:     result.Stock,
+			CreatedAt: result.CreatedAt,
+			UpdatedAt: result.UpdatedAt,
+			Highlight: &usecase.BookHighlight{
+				Title:  result.TitleHighlight,
+				Author: result.AuthorHighlight,
+			},
+		}
+	}
+
+	return &usecase.ListBooksOutput{
+		Books:      books,
+		TotalCount: totalCount,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
+	}, nil
+}
+
+// queryIndex runs the full-text query in input against the search index, returning the books
+// by relevance with the matched terms highlighted and author and price facets.
+func (b *bookUseCase) queryIndex(ctx context.Context, input usecase.ListBooksInput) (*usecase.ListBooksOutput, utils.CustomError) {
+	span := trace.SpanFromContext(ctx)
+
+	result, err := b.index.Query(ctx, search.Query{
+		Text:      input.Query,
 		Title:     input.Title,
 		Author:    input.Author,
 		MinPrice:  input.MinPrice,
 		MaxPrice:  input.MaxPrice,
 		StartDate: input.StartDate,
 		EndDate:   input.EndDate,
+		Facets:    []string{search.FacetAuthor, search.FacetPrice},
 		Limit:     input.Limit,
 		Offset:    input.Offset,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomSystemError("Search Error")
 	}
 
-	books, totalCount, err := b.repo.BookRepository().GetFiltered(ctx, filter)
+	bookIDs := make([]int64, len(result.Hits))
+	for i, hit := range result.Hits {
+		bookIDs[i] = hit.ID
+	}
+
+	found, err := b.repo.BookRepository().GetBooksByIDs(ctx, bookIDs)
 	if err != nil {
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	booksByID := make(map[int64]repository.Book, len(found))
+	for _, book := range found {
+		booksByID[book.ID] = book
+	}
+
+	// Keep the index's ranking. A hit for a book that is gone is skipped until the index catches up.
+	books := make([]usecase.Book, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		book, ok := booksByID[hit.ID]
+		if !ok {
+			continue
+		}
+		books = append(books, usecase.Book{
+			ID:        book.ID,
+			Title:     book.Title,
+			Author:    book.Author,
+			Price:     book.Price,
+			Stock:     book.Stock,
+			CreatedAt: book.CreatedAt,
+			UpdatedAt: book.UpdatedAt,
+			Highlight: &usecase.BookHighlight{
+				Title:  hit.TitleHighlight,
+				Author: hit.AuthorHighlight,
+			},
+		})
+	}
+
+	facets := make(map[string][]usecase.FacetCount, len(result.Facets))
+	for name, counts := range result.Facets {
+		facets[name] = make([]usecase.FacetCount, len(counts))
+		for i, count := range counts {
+			facets[name][i] = usecase.FacetCount{Value: count.Value, Count: count.Count}
+		}
 	}
 
 	return &usecase.ListBooksOutput{
-		Books:      convertToUsecaseBooks(books),
-		TotalCount: totalCount,
+		Books:      books,
+		TotalCount: result.Total,
 		Limit:      input.Limit,
 		Offset:     input.Offset,
+		Facets:     facets,
 	}, nil
 }
 
-// CreateBook handles creating a new book.
+// indexBook updates the search index after a book changes. The database stays the source of
+// truth, so a failure is recorded rather than returned; reindexing repairs it.
+func (b *bookUseCase) indexBook(ctx context.Context, book repository.Book) {
+	if b.index == nil {
+		return
+	}
+	if err := b.index.Index(ctx, search.NewDocument(book)); err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+	}
+}
+
+// CreateBook handles creating a new book. Its credit line is split into the authors it is
+// credited to.
 func (b *bookUseCase) CreateBook(ctx context.Context, input usecase.Book) (*usecase.Book, utils.CustomError) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.CreateBook")
 	defer span.End()
 
-	bookID, err := b.repo.BookRepository().CreateBook(ctx, &repository.Book{
+	if input.Publisher != nil && input.Publisher.Name != "" && !hasAlnum(input.Publisher.Name) {
+		return nil, utils.NewCustomUserError("Publisher name must contain a letter or digit")
+	}
+
+	now := time.Now()
+	book := repository.Book{
 		Title:     input.Title,
 		Author:    input.Author,
 		Price:     input.Price,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		Stock:     input.Stock,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	cerr := b.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		bookID, err := b.repo.BookRepository().CreateBook(txCtx, &book)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		book.ID = bookID
+
+		if err := b.creditAuthors(txCtx, bookID, book.Author, nil); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		if input.Publisher != nil {
+			return b.setPublisher(txCtx, bookID, input.Publisher.Name)
+		}
+		return nil
 	})
-	if err != nil {
-		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+	if cerr != nil {
+		return nil, cerr
 	}
 
-	return &usecase.Book{
-		ID:     bookID,
-		Title:  input.Title,
-		Author: input.Author,
-		Price:  input.Price,
-	}, nil
+	b.indexBook(ctx, book)
+
+	return b.GetBook(ctx, book.ID)
 }
 
 // GetBook retrieves a book by its ID.
@@ -85,17 +294,199 @@ func (b *bookUseCase) GetBook(ctx context.Context, id int64) (*usecase.Book, uti
 	book, err := b.repo.BookRepository().GetBookByID(ctx, id)
 	if err != nil {
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
 	}
 	if book == nil {
-		return nil, utils.NewCustomUserError("Book ID Not Found")
+		return nil, utils.NewCustomNotFoundError("Book ID Not Found")
 	}
 
-	return &usecase.Book{
-		ID:     book.ID,
-		Title:  book.Title,
-		Author: book.Author,
-		Price:  book.Price,
+	categories, err := b.repo.CategoryRepository().GetBookCategories(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	publisher, err := b.repo.PublisherRepository().GetBookPublisher(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	output := &usecase.Book{
+		ID:        book.ID,
+		Title:     book.Title,
+		Author:    book.Author,
+		Price:     book.Price,
+		Stock:     book.Stock,
+		CreatedAt: book.CreatedAt,
+		UpdatedAt: book.UpdatedAt,
+	}
+	if publisher != nil {
+		output.Publisher = &usecase.Publisher{ID: publisher.ID, Name: publisher.Name}
+	}
+	for _, category := range categories {
+		output.Categories = append(output.Categories, usecase.Category{
+			ID:        category.ID,
+			ParentID:  category.ParentID,
+			Name:      category.Name,
+			Slug:      category.Slug,
+			CreatedAt: category.CreatedAt,
+			UpdatedAt: category.UpdatedAt,
+		})
+	}
+
+	books := []usecase.Book{*output}
+	if err := b.attachAuthors(ctx, books); err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	return &books[0], nil
+}
+
+// UpdateBook applies the provided fields to an existing book. A new credit line re-credits the
+// book's authors.
+func (b *bookUseCase) UpdateBook(ctx context.Context, id int64, input usecase.UpdateBookInput) (*usecase.Book, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.UpdateBook")
+	defer span.End()
+
+	if input.Publisher != nil && input.Publisher.Name != "" && !hasAlnum(input.Publisher.Name) {
+		return nil, utils.NewCustomUserError("Publisher name must contain a letter or digit")
+	}
+
+	book, err := b.repo.BookRepository().GetBookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return nil, utils.NewCustomNotFoundError("Book ID Not Found")
+	}
+
+	recredit := input.Author != nil && *input.Author != book.Author
+
+	if input.Title != nil {
+		book.Title = *input.Title
+	}
+	if input.Author != nil {
+		book.Author = *input.Author
+	}
+	if input.Price != nil {
+		book.Price = *input.Price
+	}
+
+	cerr := b.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		if err := b.repo.BookRepository().UpdateBook(txCtx, book); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		if recredit {
+			current, err := b.repo.AuthorRepository().GetBookAuthors(txCtx, []int64{id})
+			if err != nil {
+				span.RecordError(err)
+				return utils.NewCustomDatabaseError(err)
+			}
+			if err := b.creditAuthors(txCtx, id, book.Author, current); err != nil {
+				span.RecordError(err)
+				return utils.NewCustomDatabaseError(err)
+			}
+		}
+		if input.Publisher != nil {
+			return b.setPublisher(txCtx, id, input.Publisher.Name)
+		}
+		return nil
+	})
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	b.indexBook(ctx, *book)
+
+	return b.GetBook(ctx, id)
+}
+
+// DeleteBook removes a book by its ID.
+func (b *bookUseCase) DeleteBook(ctx context.Context, id int64) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.DeleteBook")
+	defer span.End()
+
+	book, err := b.repo.BookRepository().GetBookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return utils.NewCustomNotFoundError("Book ID Not Found")
+	}
+
+	if err := b.repo.BookRepository().DeleteBook(ctx, id); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	if b.index != nil {
+		if err := b.index.Delete(ctx, id); err != nil {
+			span.RecordError(err)
+		}
+	}
+
+	return nil
+}
+
+// RestockBook adds the given quantity to a book's stock.
+func (b *bookUseCase) RestockBook(ctx context.Context, id int64, input usecase.RestockInput) (*usecase.Book, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.RestockBook")
+	defer span.End()
+
+	book, err := b.repo.BookRepository().GetBookByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return nil, utils.NewCustomNotFoundError("Book ID Not Found")
+	}
+
+	if err := b.repo.BookRepository().UpdateStock(ctx, id, input.Quantity); err != nil {
+		if errors.Is(err, repository.ErrBookNotFound) {
+			return nil, utils.NewCustomNotFoundError("Book ID Not Found")
+		}
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return b.GetBook(ctx, id)
+}
+
+// ListStock retrieves stock levels, lowest first.
+func (b *bookUseCase) ListStock(ctx context.Context, input usecase.ListStockInput) (*usecase.ListStockOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "bookUseCase.ListStock")
+	defer span.End()
+
+	books, totalCount, err := b.repo.BookRepository().GetStockLevels(ctx, repository.StockFilter{
+		MaxStock: input.MaxStock,
+		Limit:    input.Limit,
+		Offset:   input.Offset,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	items := make([]usecase.StockLevel, len(books))
+	for i, book := range books {
+		items[i] = usecase.StockLevel{
+			BookID: book.ID,
+			Title:  book.Title,
+			Stock:  book.Stock,
+		}
+	}
+
+	return &usecase.ListStockOutput{
+		Items:      items,
+		TotalCount: totalCount,
+		Limit:      input.Limit,
+		Offset:     input.Offset,
 	}, nil
 }
 
@@ -108,6 +499,7 @@ func convertToUsecaseBooks(repoBooks []repository.Book) []usecase.Book {
 			Title:     book.Title,
 			Author:    book.Author,
 			Price:     book.Price,
+			Stock:     book.Stock,
 			CreatedAt: book.CreatedAt,
 			UpdatedAt: book.UpdatedAt,
 		}
@@ -0,0 +1,187 @@
+package cart
+
+import (
+	"context"
+	"errors"
+	"math"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+type cartUseCase struct {
+	repo         repository.Repository
+	orderUseCase usecase.OrderUseCase
+}
+
+// NewCartUseCase creates a new instance of cartUseCase. Checkout is delegated to orderUseCase
+// so that carts go through the same stock and price validation as direct orders.
+func NewCartUseCase(repo repository.Repository, orderUseCase usecase.OrderUseCase) usecase.CartUseCase {
+	return &cartUseCase{
+		repo:         repo,
+		orderUseCase: orderUseCase,
+	}
+}
+
+// GetCart retrieves the user's cart priced at the current book prices.
+func (c *cartUseCase) GetCart(ctx context.Context, userID int64) (*usecase.CartOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.GetCart")
+	defer span.End()
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return c.loadCart(ctx, cartID)
+}
+
+// AddItem adds a book to the user's cart, increasing the quantity if it is already there.
+func (c *cartUseCase) AddItem(ctx context.Context, userID int64, input usecase.AddCartItemInput) (*usecase.CartOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.AddItem")
+	defer span.End()
+
+	book, err := c.repo.BookRepository().GetBookByID(ctx, input.BookID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return nil, utils.NewCustomNotFoundError("Book Not Found")
+	}
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	if err := c.repo.CartRepository().AddCartItem(ctx, cartID, input.BookID, input.Quantity); err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return c.loadCart(ctx, cartID)
+}
+
+// UpdateItem sets the quantity of a book in the user's cart.
+func (c *cartUseCase) UpdateItem(ctx context.Context, userID, bookID int64, input usecase.UpdateCartItemInput) (*usecase.CartOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.UpdateItem")
+	defer span.End()
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	if err := c.repo.CartRepository().UpdateCartItem(ctx, cartID, bookID, input.Quantity); err != nil {
+		span.RecordError(err)
+		if errors.Is(err, repository.ErrCartItemNotFound) {
+			return nil, utils.NewCustomNotFoundError("Cart Item Not Found")
+		}
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return c.loadCart(ctx, cartID)
+}
+
+// RemoveItem removes a book from the user's cart.
+func (c *cartUseCase) RemoveItem(ctx context.Context, userID, bookID int64) (*usecase.CartOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.RemoveItem")
+	defer span.End()
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	if err := c.repo.CartRepository().RemoveCartItem(ctx, cartID, bookID); err != nil {
+		span.RecordError(err)
+		if errors.Is(err, repository.ErrCartItemNotFound) {
+			return nil, utils.NewCustomNotFoundError("Cart Item Not Found")
+		}
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return c.loadCart(ctx, cartID)
+}
+
+// ClearCart removes every item from the user's cart.
+func (c *cartUseCase) ClearCart(ctx context.Context, userID int64) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.ClearCart")
+	defer span.End()
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	if err := c.repo.CartRepository().ClearCart(ctx, cartID); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	return nil
+}
+
+// Checkout places an order for the contents of the user's cart. Prices and stock are
+// re-validated by the order transaction, which also empties the cart on success. A cart holding a
+// book that has since been deleted is rejected as a whole rather than partially ordered.
+func (c *cartUseCase) Checkout(ctx context.Context, userID int64) (*usecase.CreateOrderOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "cartUseCase.Checkout")
+	defer span.End()
+
+	cartID, err := c.repo.CartRepository().GetOrCreateCart(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	output, customErr := c.orderUseCase.CreateOrder(ctx, usecase.CreateOrderInput{CartID: cartID}, userID)
+	if customErr != nil {
+		span.RecordError(customErr)
+		return nil, customErr
+	}
+
+	return output, nil
+}
+
+// loadCart reads the cart items and prices them.
+func (c *cartUseCase) loadCart(ctx context.Context, cartID int64) (*usecase.CartOutput, utils.CustomError) {
+	items, err := c.repo.CartRepository().GetCartItems(ctx, cartID)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	output := &usecase.CartOutput{Items: make([]usecase.CartItemDetail, len(items))}
+	var subtotal float64
+	for i, item := range items {
+		lineTotal := roundToCents(item.Price * float64(item.Quantity))
+		output.Items[i] = usecase.CartItemDetail{
+			BookID:      item.BookID,
+			Title:       item.Title,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.Price,
+			LineTotal:   lineTotal,
+			Available:   item.Stock,
+			Unavailable: item.BookDeleted,
+		}
+		subtotal += lineTotal
+	}
+	output.Subtotal = roundToCents(subtotal)
+
+	return output, nil
+}
+
+// roundToCents rounds an amount to two decimal places.
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,112 @@
+package cart
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+	"github.com/masatrio/bookstore-api/internal/usecase/order"
+)
+
+var cartItemColumns = []string{"id", "cart_id", "book_id", "title", "price", "stock", "deleted", "quantity"}
+
+// newTestCartUseCase wires the cart and order usecases to Postgres repositories over sqlmock.
+func newTestCartUseCase(t *testing.T) (usecase.CartUseCase, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	repo := postgresql.NewRepository(db, time.Second,
+		postgresql.NewPostgresBookRepository(db, time.Second),
+		postgresql.NewPostgresOrderRepository(db, time.Second),
+		postgresql.NewPostgresOrderItemRepository(db, time.Second),
+		postgresql.NewPostgresOrderStatusHistoryRepository(db, time.Second),
+		nil,
+		postgresql.NewPostgresCartRepository(db, time.Second),
+		nil, nil, nil, nil, nil,
+	)
+	return NewCartUseCase(repo, order.NewOrderUseCase(repo)), mock
+}
+
+func TestCheckout(t *testing.T) {
+	uc, mock := newTestCartUseCase(t)
+
+	mock.ExpectPrepare(`INSERT INTO carts`).ExpectQuery().WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`FROM cart_items ci\s+LEFT JOIN books b(.+)FOR UPDATE OF ci`).ExpectQuery().WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(cartItemColumns).AddRow(1, 3, 11, "The Hobbit", 15.5, 4, false, 2))
+	mock.ExpectPrepare(`FROM books`).ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "price", "stock", "created_at", "updated_at"}).
+			AddRow(11, "The Hobbit", "J.R.R. Tolkien", 15.5, 4, time.Now(), time.Now()))
+	mock.ExpectPrepare(`INSERT INTO orders`).ExpectQuery().WithArgs(7, usecase.OrderStatusPending, 31.0, 31.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(40))
+	mock.ExpectPrepare(`INSERT INTO order_status_history`).ExpectQuery().
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectPrepare(`INSERT INTO order_items`).ExpectQuery().WithArgs(40, 11, "The Hobbit", 2, 15.5).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectPrepare(`UPDATE books`).ExpectExec().WithArgs(-2, 11).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectPrepare(`DELETE FROM cart_items WHERE cart_id = \$1`).ExpectExec().WithArgs(3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	output, err := uc.Checkout(context.Background(), 7)
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, output) {
+		assert.Equal(t, int64(40), output.OrderID)
+		assert.Equal(t, 31.0, output.Total)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCheckout_DeletedBook(t *testing.T) {
+	uc, mock := newTestCartUseCase(t)
+
+	// A line whose book has been deleted fails the whole checkout and leaves the cart untouched.
+	mock.ExpectPrepare(`INSERT INTO carts`).ExpectQuery().WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectBegin()
+	mock.ExpectPrepare(`FROM cart_items ci\s+LEFT JOIN books b(.+)FOR UPDATE OF ci`).ExpectQuery().WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(cartItemColumns).
+			AddRow(1, 3, 11, "The Hobbit", 15.5, 4, false, 2).
+			AddRow(2, 3, 12, "", 0, 0, true, 1))
+	mock.ExpectRollback()
+
+	output, err := uc.Checkout(context.Background(), 7)
+
+	assert.Nil(t, output)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "Book ID Not Found: 12")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCart_ListsDeletedBooks(t *testing.T) {
+	uc, mock := newTestCartUseCase(t)
+
+	mock.ExpectPrepare(`INSERT INTO carts`).ExpectQuery().WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+	mock.ExpectPrepare(`FROM cart_items ci\s+LEFT JOIN books b`).ExpectQuery().WithArgs(3).
+		WillReturnRows(sqlmock.NewRows(cartItemColumns).
+			AddRow(1, 3, 11, "The Hobbit", 15.5, 4, false, 2).
+			AddRow(2, 3, 12, "", 0, 0, true, 1))
+
+	output, err := uc.GetCart(context.Background(), 7)
+
+	assert.Nil(t, err)
+	if assert.NotNil(t, output) && assert.Len(t, output.Items, 2) {
+		assert.False(t, output.Items[0].Unavailable)
+		assert.True(t, output.Items[1].Unavailable)
+		assert.Equal(t, 31.0, output.Subtotal)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,325 @@
+package category
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// slugPattern matches lowercase words of letters and digits joined by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type categoryUseCase struct {
+	repo repository.Repository
+}
+
+// NewCategoryUseCase creates a new instance of categoryUseCase.
+func NewCategoryUseCase(repo repository.Repository) usecase.CategoryUseCase {
+	return &categoryUseCase{
+		repo: repo,
+	}
+}
+
+// ListCategories retrieves every category as a tree of top-level categories.
+func (c *categoryUseCase) ListCategories(ctx context.Context) ([]usecase.Category, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.ListCategories")
+	defer span.End()
+
+	categories, err := c.repo.CategoryRepository().GetCategories(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	tree := buildTree(categories, nil)
+	if tree == nil {
+		tree = []usecase.Category{}
+	}
+	return tree, nil
+}
+
+// GetCategory retrieves a category with its subcategories.
+func (c *categoryUseCase) GetCategory(ctx context.Context, id int64) (*usecase.Category, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.GetCategory")
+	defer span.End()
+
+	categories, err := c.repo.CategoryRepository().GetCategories(ctx)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	for _, category := range categories {
+		if category.ID == id {
+			output := convertToUsecaseCategory(category)
+			output.Children = buildTree(categories, &category.ID)
+			return &output, nil
+		}
+	}
+
+	return nil, utils.NewCustomNotFoundError("Category Not Found")
+}
+
+// CreateCategory creates a category under the given parent, or at the top level.
+func (c *categoryUseCase) CreateCategory(ctx context.Context, input usecase.CategoryInput) (*usecase.Category, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.CreateCategory")
+	defer span.End()
+
+	slug, cerr := normalizeSlug(input)
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	if input.ParentID != nil {
+		parent, err := c.repo.CategoryRepository().GetCategoryByID(ctx, *input.ParentID)
+		if err != nil {
+			span.RecordError(err)
+			return nil, utils.NewCustomDatabaseError(err)
+		}
+		if parent == nil {
+			return nil, utils.NewCustomNotFoundError("Parent Category Not Found")
+		}
+	}
+
+	category := &repository.Category{
+		ParentID: input.ParentID,
+		Name:     input.Name,
+		Slug:     slug,
+	}
+
+	id, err := c.repo.CategoryRepository().CreateCategory(ctx, category)
+	if err != nil {
+		if errors.Is(err, repository.ErrCategorySlugTaken) {
+			return nil, utils.NewCustomConflictError("Category slug already exists")
+		}
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	now := time.Now()
+	return &usecase.Category{
+		ID:        id,
+		ParentID:  category.ParentID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// UpdateCategory replaces a category's name, slug and parent. A category cannot be moved under
+// itself or one of its own subcategories. The check and the move run in one serializable
+// transaction, so that two concurrent moves cannot together create a cycle; the loser is retried
+// against the winner's tree.
+func (c *categoryUseCase) UpdateCategory(ctx context.Context, id int64, input usecase.CategoryInput) (*usecase.Category, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.UpdateCategory")
+	defer span.End()
+
+	slug, cerr := normalizeSlug(input)
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	var category repository.Category
+	cerr = c.repo.WithTransaction(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable}, func(txCtx context.Context) utils.CustomError {
+		categories, err := c.repo.CategoryRepository().GetCategories(txCtx)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		byID := make(map[int64]repository.Category, len(categories))
+		for _, category := range categories {
+			byID[category.ID] = category
+		}
+
+		var ok bool
+		category, ok = byID[id]
+		if !ok {
+			return utils.NewCustomNotFoundError("Category Not Found")
+		}
+
+		if input.ParentID != nil {
+			if _, ok := byID[*input.ParentID]; !ok {
+				return utils.NewCustomNotFoundError("Parent Category Not Found")
+			}
+			if createsCycle(byID, id, *input.ParentID) {
+				return utils.NewCustomUserError("A category cannot be moved under itself or its subcategories")
+			}
+		}
+
+		category.ParentID = input.ParentID
+		category.Name = input.Name
+		category.Slug = slug
+
+		if err := c.repo.CategoryRepository().UpdateCategory(txCtx, &category); err != nil {
+			if errors.Is(err, repository.ErrCategorySlugTaken) {
+				return utils.NewCustomConflictError("Category slug already exists")
+			}
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	output := convertToUsecaseCategory(category)
+	output.UpdatedAt = time.Now()
+	return &output, nil
+}
+
+// createsCycle reports whether moving category id under parentID would make it its own ancestor.
+// It walks up from parentID and stops at a category it has already seen, so a tree that somehow
+// already contains a cycle cannot make it loop forever.
+func createsCycle(byID map[int64]repository.Category, id, parentID int64) bool {
+	seen := make(map[int64]bool)
+	for ancestor := &parentID; ancestor != nil && !seen[*ancestor]; ancestor = byID[*ancestor].ParentID {
+		if *ancestor == id {
+			return true
+		}
+		seen[*ancestor] = true
+	}
+	return false
+}
+
+// DeleteCategory removes a category. Its books keep their other categories; categories that
+// still have subcategories cannot be deleted.
+func (c *categoryUseCase) DeleteCategory(ctx context.Context, id int64) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.DeleteCategory")
+	defer span.End()
+
+	category, err := c.repo.CategoryRepository().GetCategoryByID(ctx, id)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if category == nil {
+		return utils.NewCustomNotFoundError("Category Not Found")
+	}
+
+	if err := c.repo.CategoryRepository().DeleteCategory(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrCategoryHasChildren) {
+			return utils.NewCustomConflictError("Category has subcategories")
+		}
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	return nil
+}
+
+// SetBookCategories replaces the categories a book is assigned to and returns them.
+func (c *categoryUseCase) SetBookCategories(ctx context.Context, bookID int64, input usecase.SetBookCategoriesInput) ([]usecase.Category, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "categoryUseCase.SetBookCategories")
+	defer span.End()
+
+	book, err := c.repo.BookRepository().GetBookByID(ctx, bookID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if book == nil {
+		return nil, utils.NewCustomNotFoundError("Book ID Not Found")
+	}
+
+	cerr := c.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		if err := c.repo.CategoryRepository().SetBookCategories(txCtx, bookID, input.CategoryIDs); err != nil {
+			if errors.Is(err, repository.ErrCategoryNotFound) {
+				return utils.NewCustomNotFoundError("Category Not Found")
+			}
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+	if cerr != nil {
+		return nil, cerr
+	}
+
+	categories, err := c.repo.CategoryRepository().GetBookCategories(ctx, bookID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	output := make([]usecase.Category, len(categories))
+	for i, category := range categories {
+		output[i] = convertToUsecaseCategory(category)
+	}
+	return output, nil
+}
+
+// normalizeSlug returns the input's slug, derived from the name when empty.
+func normalizeSlug(input usecase.CategoryInput) (string, utils.CustomError) {
+	slug := input.Slug
+	if slug == "" {
+		slug = slugify(input.Name)
+	}
+	if !slugPattern.MatchString(slug) {
+		return "", utils.NewCustomUserError("Slug must contain only lowercase letters, digits and single hyphens")
+	}
+	return slug, nil
+}
+
+// slugify lowercases name and joins its runs of letters and digits with hyphens.
+func slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	return strings.Join(words, "-")
+}
+
+// buildTree returns the children of parentID, each with its own subtree, in the order of
+// categories. A nil parentID returns the top-level categories.
+func buildTree(categories []repository.Category, parentID *int64) []usecase.Category {
+	children := make(map[int64][]repository.Category)
+	var roots []repository.Category
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var build func(nodes []repository.Category) []usecase.Category
+	build = func(nodes []repository.Category) []usecase.Category {
+		if len(nodes) == 0 {
+			return nil
+		}
+		output := make([]usecase.Category, len(nodes))
+		for i, node := range nodes {
+			output[i] = convertToUsecaseCategory(node)
+			output[i].Children = build(children[node.ID])
+		}
+		return output
+	}
+
+	if parentID == nil {
+		return build(roots)
+	}
+	return build(children[*parentID])
+}
+
+// convertToUsecaseCategory converts a repository category to a usecase category.
+func convertToUsecaseCategory(category repository.Category) usecase.Category {
+	return usecase.Category{
+		ID:        category.ID,
+		ParentID:  category.ParentID,
+		Name:      category.Name,
+		Slug:      category.Slug,
+		CreatedAt: category.CreatedAt,
+		UpdatedAt: category.UpdatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package category
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+)
+
+func TestNormalizeSlug(t *testing.T) {
+	slug, err := normalizeSlug(usecase.CategoryInput{Name: "  Science Fiction & Fantasy!"})
+	assert.Nil(t, err)
+	assert.Equal(t, "science-fiction-fantasy", slug)
+
+	slug, err = normalizeSlug(usecase.CategoryInput{Name: "Sci-Fi", Slug: "scifi"})
+	assert.Nil(t, err)
+	assert.Equal(t, "scifi", slug)
+
+	_, err = normalizeSlug(usecase.CategoryInput{Name: "Sci-Fi", Slug: "Sci Fi"})
+	assert.NotNil(t, err)
+
+	// Names without any letters or digits cannot produce a slug.
+	_, err = normalizeSlug(usecase.CategoryInput{Name: "!!!"})
+	assert.NotNil(t, err)
+}
+
+func TestBuildTree(t *testing.T) {
+	fiction, fantasy, epic, history := int64(1), int64(2), int64(3), int64(4)
+	categories := []repository.Category{
+		{ID: epic, ParentID: &fantasy, Name: "Epic", Slug: "epic"},
+		{ID: fantasy, ParentID: &fiction, Name: "Fantasy", Slug: "fantasy"},
+		{ID: fiction, Name: "Fiction", Slug: "fiction"},
+		{ID: history, Name: "History", Slug: "history"},
+	}
+
+	tree := buildTree(categories, nil)
+	if assert.Len(t, tree, 2) {
+		assert.Equal(t, "fiction", tree[0].Slug)
+		assert.Equal(t, "history", tree[1].Slug)
+		assert.Empty(t, tree[1].Children)
+		if assert.Len(t, tree[0].Children, 1) {
+			assert.Equal(t, "fantasy", tree[0].Children[0].Slug)
+			if assert.Len(t, tree[0].Children[0].Children, 1) {
+				assert.Equal(t, "epic", tree[0].Children[0].Children[0].Slug)
+			}
+		}
+	}
+
+	subtree := buildTree(categories, &fantasy)
+	if assert.Len(t, subtree, 1) {
+		assert.Equal(t, "epic", subtree[0].Slug)
+	}
+}
+
+func TestCreatesCycle(t *testing.T) {
+	fiction, fantasy, epic, history := int64(1), int64(2), int64(3), int64(4)
+	byID := map[int64]repository.Category{
+		fiction: {ID: fiction},
+		fantasy: {ID: fantasy, ParentID: &fiction},
+		epic:    {ID: epic, ParentID: &fantasy},
+		history: {ID: history},
+	}
+
+	assert.True(t, createsCycle(byID, fiction, fiction))
+	assert.True(t, createsCycle(byID, fiction, epic))
+	assert.False(t, createsCycle(byID, epic, fiction))
+	assert.False(t, createsCycle(byID, fiction, history))
+
+	// A cycle already in the data must not make the walk loop forever.
+	loopA, loopB := int64(5), int64(6)
+	byID[loopA] = repository.Category{ID: loopA, ParentID: &loopB}
+	byID[loopB] = repository.Category{ID: loopB, ParentID: &loopA}
+	assert.False(t, createsCycle(byID, history, loopA))
+}
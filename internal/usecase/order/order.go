@@ -2,8 +2,16 @@ package order
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/masatrio/bookstore-api/internal/domain/repository" // Adjust this import based on your repository structure
@@ -11,7 +19,10 @@ import (
 	"github.com/masatrio/bookstore-api/utils"
 )
 
-const successOrderStatus string = "success"
+var ordersCreated, _ = otel.Meter("github.com/masatrio/bookstore-api/internal/usecase/order").Int64Counter(
+	"bookstore.orders.created",
+	metric.WithDescription("Number of orders placed, by source (cart checkout or direct)."),
+)
 
 type orderUseCase struct {
 	repo repository.Repository
@@ -30,37 +41,114 @@ func (o *orderUseCase) CreateOrder(ctx context.Context, input usecase.CreateOrde
 	defer span.End()
 
 	var orderID int64
-	err := o.repo.WithTransaction(func(txCtx context.Context) utils.CustomError {
+	var items []*repository.OrderItem
+	var subtotal float64
+	err := o.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		if input.CartID != 0 {
+			cartItems, err := o.repo.CartRepository().GetCartItemsForUpdate(txCtx, input.CartID)
+			if err != nil {
+				span.RecordError(err)
+				return utils.NewCustomDatabaseError(err)
+			}
+			if len(cartItems) == 0 {
+				return utils.NewCustomUserError("Cart is empty")
+			}
+
+			input.Items = make([]usecase.OrderItem, len(cartItems))
+			var deleted []string
+			for i, item := range cartItems {
+				input.Items[i] = usecase.OrderItem{BookID: item.BookID, Quantity: item.Quantity}
+				if item.BookDeleted {
+					deleted = append(deleted, strconv.FormatInt(item.BookID, 10))
+				}
+			}
+			if len(deleted) > 0 {
+				return utils.NewCustomUserError("Book ID Not Found: " + strings.Join(deleted, ", ") + ". Remove unavailable books from the cart to check out")
+			}
+		}
+
+		quantities, bookIDs := groupQuantities(input.Items)
+
+		books, err := o.repo.BookRepository().GetBooksForUpdate(txCtx, bookIDs)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		if len(books) != len(bookIDs) {
+			return utils.NewCustomUserError("Book ID Not Found")
+		}
+
+		booksByID := make(map[int64]repository.Book, len(books))
+		var shortages []string
+		for _, book := range books {
+			booksByID[book.ID] = book
+			if requested := quantities[book.ID]; requested > book.Stock {
+				shortages = append(shortages, fmt.Sprintf("%s (book %d): requested %d, available %d", book.Title, book.ID, requested, book.Stock))
+			}
+		}
+		if len(shortages) > 0 {
+			return utils.NewCustomUserError("Insufficient stock for: " + strings.Join(shortages, "; "))
+		}
+
+		// Snapshot title and price so later catalog changes don't rewrite order history.
+		items = make([]*repository.OrderItem, len(input.Items))
+		for i, item := range input.Items {
+			book := booksByID[item.BookID]
+			items[i] = &repository.OrderItem{
+				BookID:    item.BookID,
+				Title:     book.Title,
+				Quantity:  item.Quantity,
+				UnitPrice: book.Price,
+			}
+		}
+		subtotal = computeSubtotal(items)
 
-		var err error
 		orderID, err = o.repo.OrderRepository().CreateOrder(txCtx, &repository.Order{
-			UserID: userID,
-			Status: successOrderStatus,
+			UserID:   userID,
+			Status:   usecase.OrderStatusPending,
+			Subtotal: subtotal,
+			Total:    subtotal,
 		})
-
 		if err != nil {
 			span.RecordError(err)
-			return utils.NewCustomSystemError("Database 3 Error")
+			return utils.NewCustomDatabaseError(err)
 		}
 
-		for _, item := range input.Items {
-			book, err := o.repo.BookRepository().GetBookByID(txCtx, item.BookID)
-			if err != nil {
+		if _, err := o.repo.OrderStatusHistoryRepository().CreateStatusHistory(txCtx, &repository.OrderStatusHistory{
+			OrderID:   orderID,
+			ToStatus:  usecase.OrderStatusPending,
+			ChangedBy: userID,
+		}); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		for _, item := range items {
+			item.OrderID = orderID
+			if _, err := o.repo.OrderItemRepository().CreateOrderItem(txCtx, item); err != nil {
 				span.RecordError(err)
-				return utils.NewCustomSystemError("Database 2 Error")
+				return utils.NewCustomDatabaseError(err)
 			}
+		}
 
-			if book == nil {
-				return utils.NewCustomUserError("Book ID Not Found")
+		for _, bookID := range bookIDs {
+			if err := o.repo.BookRepository().UpdateStock(txCtx, bookID, -quantities[bookID]); err != nil {
+				span.RecordError(err)
+				if errors.Is(err, repository.ErrInsufficientStock) {
+					return utils.NewCustomUserError("Insufficient stock")
+				}
+				if errors.Is(err, repository.ErrBookNotFound) {
+					return utils.NewCustomUserError("Book ID Not Found")
+				}
+				return utils.NewCustomDatabaseError(err)
 			}
+		}
 
-			if _, err := o.repo.OrderItemRepository().CreateOrderItem(txCtx, &repository.OrderItem{
-				OrderID:  orderID,
-				BookID:   item.BookID,
-				Quantity: item.Quantity,
-			}); err != nil {
+		if input.CartID != 0 {
+			if err := o.repo.CartRepository().ClearCart(txCtx, input.CartID); err != nil {
 				span.RecordError(err)
-				return utils.NewCustomSystemError("Database 3 Error")
+				return utils.NewCustomDatabaseError(err)
 			}
 		}
 
@@ -72,49 +160,162 @@ func (o *orderUseCase) CreateOrder(ctx context.Context, input usecase.CreateOrde
 		return nil, err
 	}
 
+	source := "direct"
+	if input.CartID != 0 {
+		source = "cart"
+	}
+	ordersCreated.Add(ctx, 1, metric.WithAttributes(attribute.String("source", source)))
+
 	return &usecase.CreateOrderOutput{
 		OrderID:   orderID,
-		Items:     input.Items,
-		Status:    successOrderStatus,
+		Items:     convertToOrderItemDetails(items),
+		Status:    usecase.OrderStatusPending,
+		Subtotal:  subtotal,
+		Total:     subtotal,
 		CreatedAt: time.Now().Format(time.RFC3339),
 	}, nil
 }
 
-// GetOrders retrieves user orders by userID with pagination.
-func (o *orderUseCase) GetOrders(ctx context.Context, userID int64, limit, offset int) ([]usecase.GetOrderOutput, utils.CustomError) {
+// computeSubtotal sums the line totals of the given items, rounded to cents.
+func computeSubtotal(items []*repository.OrderItem) float64 {
+	var subtotal float64
+	for _, item := range items {
+		subtotal += roundToCents(item.UnitPrice * float64(item.Quantity))
+	}
+	return roundToCents(subtotal)
+}
+
+// roundToCents rounds an amount to two decimal places.
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// convertToOrderItemDetails converts repository order items to usecase order item details.
+func convertToOrderItemDetails(items []*repository.OrderItem) []usecase.OrderItemDetail {
+	details := make([]usecase.OrderItemDetail, len(items))
+	for i, item := range items {
+		details[i] = usecase.OrderItemDetail{
+			BookID:    item.BookID,
+			Title:     item.Title,
+			Quantity:  item.Quantity,
+			UnitPrice: item.UnitPrice,
+			LineTotal: roundToCents(item.UnitPrice * float64(item.Quantity)),
+		}
+	}
+	return details
+}
+
+// groupQuantities sums the requested quantity per book, returning the distinct book IDs in request order.
+func groupQuantities(items []usecase.OrderItem) (map[int64]int, []int64) {
+	quantities := make(map[int64]int, len(items))
+	var bookIDs []int64
+	for _, item := range items {
+		if _, ok := quantities[item.BookID]; !ok {
+			bookIDs = append(bookIDs, item.BookID)
+		}
+		quantities[item.BookID] += item.Quantity
+	}
+	return quantities, bookIDs
+}
+
+// GetOrders retrieves user orders by userID, paged by offset or by the cursor of a previous page.
+func (o *orderUseCase) GetOrders(ctx context.Context, userID int64, input usecase.GetOrdersInput) (*usecase.GetOrdersOutput, utils.CustomError) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "orderUseCase.GetOrders")
 	defer span.End()
 
-	orders, err := o.repo.OrderRepository().GetOrdersByUserID(ctx, userID, limit, offset)
+	if input.Limit < 0 || input.Offset < 0 {
+		return nil, utils.NewCustomUserError("limit and offset must not be negative")
+	}
+	if _, err := repository.ParseSort(input.Sort, repository.OrderSortFields); err != nil {
+		return nil, utils.NewCustomUserError(err.Error())
+	}
+
+	// One extra order tells whether another page follows.
+	orders, err := o.repo.OrderRepository().GetOrdersByUserID(ctx, userID, repository.OrderFilter{
+		Sort:   input.Sort,
+		Cursor: input.Cursor,
+		Limit:  input.Limit + 1,
+		Offset: input.Offset,
+	})
 	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, utils.NewCustomUserError("Invalid cursor")
+		}
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
 	}
 
-	var output []usecase.GetOrderOutput
-	for _, order := range orders {
-
-		items, err := o.repo.OrderItemRepository().GetOrderItemsByOrderID(ctx, order.ID)
-		if err != nil {
-			span.RecordError(err)
-			return nil, utils.NewCustomSystemError("Database Error")
+	var nextCursor string
+	if len(orders) > input.Limit {
+		orders = orders[:input.Limit]
+		if input.Limit > 0 {
+			nextCursor = repository.NewOrderCursor(input.Sort, orders[input.Limit-1])
 		}
+	}
 
-		var orderItems []usecase.OrderItem
-		for _, item := range items {
-			orderItems = append(orderItems, usecase.OrderItem{
-				BookID:   item.BookID,
-				Quantity: item.Quantity,
-			})
-		}
+	orderIDs := make([]int64, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+
+	itemsByOrderID, err := o.repo.OrderItemRepository().GetOrderItemsByOrderIDs(ctx, orderIDs)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
 
+	var output []usecase.GetOrderOutput
+	for _, order := range orders {
 		output = append(output, usecase.GetOrderOutput{
 			OrderID:   order.ID,
-			Items:     orderItems,
+			Items:     convertToOrderItemDetails(itemsByOrderID[order.ID]),
 			Status:    order.Status,
+			Subtotal:  order.Subtotal,
+			Total:     order.Total,
 			CreatedAt: order.CreatedAt.Format(time.RFC3339),
 		})
 	}
 
-	return output, nil
+	return &usecase.GetOrdersOutput{
+		Orders:     output,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// GetOrder retrieves a single order with its items and status history. Orders that belong to
+// another user are reported as not found so that order IDs cannot be enumerated.
+func (o *orderUseCase) GetOrder(ctx context.Context, orderID, userID int64) (*usecase.GetOrderOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "orderUseCase.GetOrder")
+	defer span.End()
+
+	order, err := o.repo.OrderRepository().GetOrderByID(ctx, orderID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if order == nil || order.UserID != userID {
+		return nil, utils.NewCustomNotFoundError("Order Not Found")
+	}
+
+	items, err := o.repo.OrderItemRepository().GetOrderItemsByOrderID(ctx, order.ID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	history, err := o.repo.OrderStatusHistoryRepository().GetStatusHistoryByOrderID(ctx, order.ID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return &usecase.GetOrderOutput{
+		OrderID:   order.ID,
+		Items:     convertToOrderItemDetails(items),
+		Status:    order.Status,
+		Subtotal:  order.Subtotal,
+		Total:     order.Total,
+		History:   convertToStatusChanges(history),
+		CreatedAt: order.CreatedAt.Format(time.RFC3339),
+	}, nil
 }
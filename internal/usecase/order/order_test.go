@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/internal/repository/db/postgresql"
+)
+
+// BenchmarkGetOrders shows that loading a page of orders costs the same number of database
+// round trips regardless of the page size. Each prepared statement counts as two round trips
+// (prepare and execute).
+func BenchmarkGetOrders(b *testing.B) {
+	for _, pageSize := range []int{1, 10, 100} {
+		b.Run(fmt.Sprintf("page_size_%d", pageSize), func(b *testing.B) {
+			var roundTrips int64
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+					atomic.AddInt64(&roundTrips, 1)
+					return sqlmock.QueryMatcherRegexp.Match(expectedSQL, actualSQL)
+				})))
+				if err != nil {
+					b.Fatalf("failed to open sqlmock: %v", err)
+				}
+
+				orderRows := sqlmock.NewRows([]string{"id", "user_id", "status", "subtotal", "total", "created_at", "updated_at"})
+				itemRows := sqlmock.NewRows([]string{"id", "order_id", "book_id", "title", "quantity", "unit_price"})
+				for id := 1; id <= pageSize; id++ {
+					orderRows.AddRow(id, 1, "paid", 150000, 150000, time.Now(), time.Now())
+					itemRows.AddRow(id, id, 1, "The Hobbit", 1, 150000)
+				}
+
+				mock.ExpectPrepare("SELECT (.+) FROM orders").ExpectQuery().WillReturnRows(orderRows)
+				mock.ExpectPrepare("SELECT (.+) FROM order_items").ExpectQuery().WillReturnRows(itemRows)
+
+				repo := postgresql.NewRepository(db, time.Second, nil,
+					postgresql.NewPostgresOrderRepository(db, time.Second),
+					postgresql.NewPostgresOrderItemRepository(db, time.Second),
+					nil, nil, nil, nil, nil, nil, nil, nil,
+				)
+				uc := NewOrderUseCase(repo)
+				b.StartTimer()
+
+				output, cerr := uc.GetOrders(context.Background(), 1, usecase.GetOrdersInput{Limit: pageSize})
+				if cerr != nil {
+					b.Fatalf("unexpected error: %v", cerr)
+				}
+
+				b.StopTimer()
+				if len(output.Orders) != pageSize || len(output.Orders[pageSize-1].Items) != 1 {
+					b.Fatalf("expected %d orders with one item each, got %d", pageSize, len(output.Orders))
+				}
+				if err := mock.ExpectationsWereMet(); err != nil {
+					b.Fatalf("there were unfulfilled expectations: %s", err)
+				}
+				db.Close()
+				b.StartTimer()
+			}
+
+			b.ReportMetric(float64(roundTrips)/float64(b.N), "round_trips/op")
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/repository"
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// orderTransitions lists the statuses each status may move to.
+//
+//	pending -> paid -> shipped -> delivered
+//	   |        |                    |
+//	   v        v                    v
+//	cancelled  cancelled/refunded  refunded
+var orderTransitions = map[string][]string{
+	usecase.OrderStatusPending:   {usecase.OrderStatusPaid, usecase.OrderStatusCancelled},
+	usecase.OrderStatusPaid:      {usecase.OrderStatusShipped, usecase.OrderStatusCancelled, usecase.OrderStatusRefunded},
+	usecase.OrderStatusShipped:   {usecase.OrderStatusDelivered},
+	usecase.OrderStatusDelivered: {usecase.OrderStatusRefunded},
+	usecase.OrderStatusCancelled: {},
+	usecase.OrderStatusRefunded:  {},
+}
+
+// canTransition reports whether an order may move from one status to another.
+func canTransition(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// releasesStock reports whether a transition puts the ordered books back into stock,
+// which is the case when an order is called off before it ships.
+func releasesStock(from, to string) bool {
+	if to != usecase.OrderStatusCancelled && to != usecase.OrderStatusRefunded {
+		return false
+	}
+	return from == usecase.OrderStatusPending || from == usecase.OrderStatusPaid
+}
+
+// UpdateOrderStatus moves an order to a new status on behalf of an administrator.
+func (o *orderUseCase) UpdateOrderStatus(ctx context.Context, orderID int64, input usecase.UpdateOrderStatusInput, actorID int64) (*usecase.OrderStatusOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "orderUseCase.UpdateOrderStatus")
+	defer span.End()
+
+	if _, ok := orderTransitions[input.Status]; !ok {
+		return nil, utils.NewCustomUserError("Invalid order status")
+	}
+
+	return o.transition(ctx, span, orderID, input.Status, actorID, input.Note, nil)
+}
+
+// CancelOrder cancels an order on behalf of its owner. Orders that have shipped can no longer be cancelled.
+func (o *orderUseCase) CancelOrder(ctx context.Context, orderID, userID int64) (*usecase.OrderStatusOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "orderUseCase.CancelOrder")
+	defer span.End()
+
+	return o.transition(ctx, span, orderID, usecase.OrderStatusCancelled, userID, "cancelled by customer", &userID)
+}
+
+// transition validates and applies a status change inside a transaction, recording it in the
+// status history and releasing stock when needed. When ownerID is set, orders belonging to
+// other users are reported as not found.
+func (o *orderUseCase) transition(ctx context.Context, span trace.Span, orderID int64, toStatus string, actorID int64, note string, ownerID *int64) (*usecase.OrderStatusOutput, utils.CustomError) {
+	span.SetAttributes(attribute.Int64("order.id", orderID), attribute.String("order.to_status", toStatus))
+
+	err := o.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		order, err := o.repo.OrderRepository().GetOrderByID(txCtx, orderID)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		if order == nil || (ownerID != nil && order.UserID != *ownerID) {
+			return utils.NewCustomNotFoundError("Order Not Found")
+		}
+
+		if !canTransition(order.Status, toStatus) {
+			return utils.NewCustomConflictError(fmt.Sprintf("Cannot change order status from %s to %s", order.Status, toStatus))
+		}
+
+		if err := o.repo.OrderRepository().UpdateOrderStatus(txCtx, orderID, order.Status, toStatus); err != nil {
+			span.RecordError(err)
+			if errors.Is(err, repository.ErrOrderStatusConflict) {
+				return utils.NewCustomConflictError("Order status changed, please retry")
+			}
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		if _, err := o.repo.OrderStatusHistoryRepository().CreateStatusHistory(txCtx, &repository.OrderStatusHistory{
+			OrderID:    orderID,
+			FromStatus: order.Status,
+			ToStatus:   toStatus,
+			ChangedBy:  actorID,
+			Note:       note,
+		}); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		if releasesStock(order.Status, toStatus) {
+			items, err := o.repo.OrderItemRepository().GetOrderItemsByOrderID(txCtx, orderID)
+			if err != nil {
+				span.RecordError(err)
+				return utils.NewCustomDatabaseError(err)
+			}
+			for _, item := range items {
+				if err := o.repo.BookRepository().UpdateStock(txCtx, item.BookID, item.Quantity); err != nil {
+					// The book has been removed from the catalog, so there is no stock to return it to.
+					if errors.Is(err, repository.ErrBookNotFound) {
+						continue
+					}
+					span.RecordError(err)
+					return utils.NewCustomDatabaseError(err)
+				}
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	history, herr := o.repo.OrderStatusHistoryRepository().GetStatusHistoryByOrderID(ctx, orderID)
+	if herr != nil {
+		span.RecordError(herr)
+		return nil, utils.NewCustomDatabaseError(herr)
+	}
+
+	return &usecase.OrderStatusOutput{
+		OrderID: orderID,
+		Status:  toStatus,
+		History: convertToStatusChanges(history),
+	}, nil
+}
+
+// convertToStatusChanges converts repository status history entries to usecase status changes.
+func convertToStatusChanges(history []*repository.OrderStatusHistory) []usecase.OrderStatusChange {
+	changes := make([]usecase.OrderStatusChange, len(history))
+	for i, entry := range history {
+		changes[i] = usecase.OrderStatusChange{
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			ChangedBy:  entry.ChangedBy,
+			Note:       entry.Note,
+			ChangedAt:  entry.CreatedAt.Format(time.RFC3339),
+		}
+	}
+	return changes
+}
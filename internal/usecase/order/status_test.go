@@ -0,0 +1,44 @@
+package order
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{usecase.OrderStatusPending, usecase.OrderStatusPaid, true},
+		{usecase.OrderStatusPending, usecase.OrderStatusCancelled, true},
+		{usecase.OrderStatusPending, usecase.OrderStatusShipped, false},
+		{usecase.OrderStatusPaid, usecase.OrderStatusShipped, true},
+		{usecase.OrderStatusPaid, usecase.OrderStatusCancelled, true},
+		{usecase.OrderStatusPaid, usecase.OrderStatusRefunded, true},
+		{usecase.OrderStatusShipped, usecase.OrderStatusDelivered, true},
+		{usecase.OrderStatusShipped, usecase.OrderStatusCancelled, false},
+		{usecase.OrderStatusDelivered, usecase.OrderStatusRefunded, true},
+		{usecase.OrderStatusDelivered, usecase.OrderStatusCancelled, false},
+		{usecase.OrderStatusCancelled, usecase.OrderStatusPaid, false},
+		{usecase.OrderStatusRefunded, usecase.OrderStatusPaid, false},
+		{"unknown", usecase.OrderStatusPaid, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			assert.Equal(t, tt.expected, canTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestReleasesStock(t *testing.T) {
+	assert.True(t, releasesStock(usecase.OrderStatusPending, usecase.OrderStatusCancelled))
+	assert.True(t, releasesStock(usecase.OrderStatusPaid, usecase.OrderStatusRefunded))
+	assert.False(t, releasesStock(usecase.OrderStatusDelivered, usecase.OrderStatusRefunded))
+	assert.False(t, releasesStock(usecase.OrderStatusPaid, usecase.OrderStatusShipped))
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/utils"
+)
+
+// loginThrottle is the backoff and lockout policy for one kind of login attempt key.
+type loginThrottle struct {
+	name         string
+	freeAttempts int           // failures allowed before backoff starts
+	lockoutAfter int           // failures after which the key is locked out
+	baseDelay    time.Duration // backoff after the first throttled failure, doubled for each one after
+	lockout      time.Duration
+}
+
+var (
+	accountLoginThrottle = loginThrottle{name: "account", freeAttempts: 3, lockoutAfter: 10, baseDelay: time.Second, lockout: 15 * time.Minute}
+	// A single IP may serve many users behind a NAT, so it is allowed more failures.
+	ipLoginThrottle = loginThrottle{name: "ip", freeAttempts: 20, lockoutAfter: 100, baseDelay: time.Second, lockout: 15 * time.Minute}
+)
+
+// delay returns how long after the last failure the next attempt is allowed.
+func (t loginThrottle) delay(failures int) time.Duration {
+	if failures >= t.lockoutAfter {
+		return t.lockout
+	}
+	if failures < t.freeAttempts {
+		return 0
+	}
+
+	delay := t.baseDelay << (failures - t.freeAttempts)
+	if delay <= 0 || delay > t.lockout {
+		return t.lockout
+	}
+	return delay
+}
+
+type loginAttemptKey struct {
+	key      string
+	throttle loginThrottle
+}
+
+// loginAttemptKeys returns the keys a login attempt is counted against, the account first.
+func loginAttemptKeys(input usecase.LoginInput) []loginAttemptKey {
+	keys := []loginAttemptKey{{
+		key:      "account:" + strings.ToLower(strings.TrimSpace(input.Email)),
+		throttle: accountLoginThrottle,
+	}}
+	if input.IP != "" {
+		keys = append(keys, loginAttemptKey{key: "ip:" + input.IP, throttle: ipLoginThrottle})
+	}
+	return keys
+}
+
+// checkLoginThrottle rejects the attempt while any of its keys is backing off or locked out.
+// Store errors are recorded but do not block logins.
+func (u *userUseCase) checkLoginThrottle(ctx context.Context, span trace.Span, keys []loginAttemptKey) utils.CustomError {
+	for _, k := range keys {
+		attempts, err := u.loginAttempts.Get(ctx, k.key)
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+
+		retryAt := attempts.LastFailure.Add(k.throttle.delay(attempts.Failures))
+		if attempts.Failures == 0 || !time.Now().Before(retryAt) {
+			continue
+		}
+
+		event := "login_backoff"
+		if attempts.Failures >= k.throttle.lockoutAfter {
+			event = "login_locked_out"
+		}
+		span.AddEvent(event, trace.WithAttributes(
+			attribute.String("login.key_type", k.throttle.name),
+			attribute.Int("login.failures", attempts.Failures),
+			attribute.String("login.retry_at", retryAt.Format(time.RFC3339)),
+		))
+		span.SetStatus(codes.Error, "Too many failed login attempts")
+		return utils.NewCustomTooManyRequestsError("too many failed login attempts, try again later")
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed attempt against every key and records a span event when
+// a key becomes locked out.
+func (u *userUseCase) recordLoginFailure(ctx context.Context, span trace.Span, keys []loginAttemptKey) {
+	for _, k := range keys {
+		attempts, err := u.loginAttempts.RecordFailure(ctx, k.key, k.throttle.lockout)
+		if err != nil {
+			span.RecordError(err)
+			continue
+		}
+
+		if attempts.Failures == k.throttle.lockoutAfter {
+			span.AddEvent("login_lockout", trace.WithAttributes(
+				attribute.String("login.key_type", k.throttle.name),
+				attribute.Int("login.failures", attempts.Failures),
+				attribute.String("login.locked_until", attempts.LastFailure.Add(k.throttle.lockout).Format(time.RFC3339)),
+			))
+		}
+	}
+}
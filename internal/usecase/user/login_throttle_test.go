@@ -0,0 +1,50 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/masatrio/bookstore-api/internal/domain/usecase"
+	"github.com/masatrio/bookstore-api/internal/repository/cache/memory"
+)
+
+func TestLoginThrottleDelay(t *testing.T) {
+	throttle := loginThrottle{freeAttempts: 3, lockoutAfter: 6, baseDelay: time.Second, lockout: time.Minute}
+
+	assert.Equal(t, time.Duration(0), throttle.delay(0))
+	assert.Equal(t, time.Duration(0), throttle.delay(2))
+	assert.Equal(t, time.Second, throttle.delay(3))
+	assert.Equal(t, 2*time.Second, throttle.delay(4))
+	assert.Equal(t, 4*time.Second, throttle.delay(5))
+	assert.Equal(t, time.Minute, throttle.delay(6))
+	assert.Equal(t, time.Minute, throttle.delay(100))
+}
+
+func TestLoginThrottleLocksOutAccount(t *testing.T) {
+	ctx := context.Background()
+	span := trace.SpanFromContext(ctx)
+	u := &userUseCase{loginAttempts: memory.NewMemoryLoginAttemptStore()}
+
+	keys := loginAttemptKeys(usecase.LoginInput{Email: " Satrio@Example.com", IP: "192.0.2.1"})
+	assert.Equal(t, "account:satrio@example.com", keys[0].key)
+	assert.Equal(t, "ip:192.0.2.1", keys[1].key)
+
+	for i := 0; i < accountLoginThrottle.freeAttempts; i++ {
+		assert.Nil(t, u.checkLoginThrottle(ctx, span, keys))
+		u.recordLoginFailure(ctx, span, keys)
+	}
+
+	err := u.checkLoginThrottle(ctx, span, keys)
+	assert.NotNil(t, err)
+	assert.True(t, err.IsTooManyRequestsError())
+
+	// Another account from a different IP is unaffected.
+	assert.Nil(t, u.checkLoginThrottle(ctx, span, loginAttemptKeys(usecase.LoginInput{Email: "other@example.com", IP: "192.0.2.2"})))
+
+	assert.NoError(t, u.loginAttempts.Reset(ctx, keys[0].key))
+	assert.Nil(t, u.checkLoginThrottle(ctx, span, keys))
+}
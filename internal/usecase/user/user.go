@@ -2,27 +2,63 @@ package user
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/masatrio/bookstore-api/config"
+	"github.com/masatrio/bookstore-api/internal/domain/cache"
+	"github.com/masatrio/bookstore-api/internal/domain/mailer"
 	"github.com/masatrio/bookstore-api/internal/domain/repository"
 	"github.com/masatrio/bookstore-api/internal/domain/usecase"
 	"github.com/masatrio/bookstore-api/utils"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	passwordResetTokenTTL     = time.Hour
+	emailVerificationTokenTTL = 48 * time.Hour
+)
+
+// invalidCredentialsMessage is returned for every failed login so that responses do not reveal
+// whether an account exists.
+const invalidCredentialsMessage = "invalid email or password"
+
+// dummyPasswordHash is compared against when the account does not exist, so that unknown
+// emails take as long to reject as wrong passwords.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+var (
+	meter            = otel.Meter("github.com/masatrio/bookstore-api/internal/usecase/user")
+	registrations, _ = meter.Int64Counter("bookstore.users.registered",
+		metric.WithDescription("Number of accounts registered."),
+	)
+	failedLogins, _ = meter.Int64Counter("bookstore.logins.failed",
+		metric.WithDescription("Number of rejected logins, by reason (invalid_credentials or throttled)."),
+	)
+)
+
 type userUseCase struct {
-	repo      repository.Repository
-	jwtSecret string
-	jwtExpiry time.Duration
+	repo          repository.Repository
+	denylist      cache.TokenDenylist
+	loginAttempts cache.LoginAttemptStore
+	mailer        mailer.Mailer
+	jwtSecret     string
+	jwtExpiry     time.Duration
 }
 
 // NewUserUseCase creates a new instance of userUseCase.
-func NewUserUseCase(repo repository.Repository, jwtSecret string, jwtExpiry time.Duration) usecase.UserUseCase {
+func NewUserUseCase(repo repository.Repository, denylist cache.TokenDenylist, loginAttempts cache.LoginAttemptStore, mailer mailer.Mailer, jwtSecret string, jwtExpiry time.Duration) usecase.UserUseCase {
 	return &userUseCase{
-		repo: repo,
+		repo:          repo,
+		denylist:      denylist,
+		loginAttempts: loginAttempts,
+		mailer:        mailer,
 	}
 }
 
@@ -34,7 +70,7 @@ func (u *userUseCase) Register(ctx context.Context, input usecase.RegisterInput)
 	existingUser, err := u.repo.UserRepository().GetByEmail(ctx, input.Email)
 	if err != nil {
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
 	}
 	if existingUser != nil {
 		span.SetStatus(codes.Error, "Email is already registered")
@@ -51,51 +87,76 @@ func (u *userUseCase) Register(ctx context.Context, input usecase.RegisterInput)
 		Name:     input.Name,
 		Email:    input.Email,
 		Password: string(hashedPassword),
+		Role:     usecase.RoleCustomer,
 	})
 	if err != nil {
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
 	}
 
-	token, err := utils.GenerateJWT(userID, input.Email, config.LoadConfig().JWT.Secret, config.LoadConfig().JWT.Expiry)
+	token, refreshToken, err := u.issueTokens(ctx, userID, input.Email, usecase.RoleCustomer, "")
 	if err != nil {
 		span.RecordError(err)
 		return nil, utils.NewCustomSystemError("System Error")
 	}
 
+	// The account is usable without verification, so a mail failure must not fail registration.
+	if err := u.sendEmailVerification(ctx, userID, input.Email); err != nil {
+		span.RecordError(err)
+	}
+
+	registrations.Add(ctx, 1)
+
 	span.SetStatus(codes.Ok, "Registration successful")
 	return &usecase.RegisterOutput{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 		User: usecase.User{
 			ID:    userID,
 			Name:  input.Name,
 			Email: input.Email,
+			Role:  usecase.RoleCustomer,
 		},
 	}, nil
 }
 
-// Login handles user login.
+// Login handles user login. Failed attempts are counted per account and per client IP; once a
+// key has failed too often, further attempts are rejected with exponential backoff and finally
+// a temporary lockout.
 func (u *userUseCase) Login(ctx context.Context, input usecase.LoginInput) (*usecase.LoginOutput, utils.CustomError) {
 	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.Login")
 	defer span.End()
 
+	keys := loginAttemptKeys(input)
+	if customErr := u.checkLoginThrottle(ctx, span, keys); customErr != nil {
+		failedLogins.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "throttled")))
+		return nil, customErr
+	}
+
 	user, err := u.repo.UserRepository().GetByEmail(ctx, input.Email)
 	if err != nil {
 		span.RecordError(err)
-		return nil, utils.NewCustomSystemError("Database Error")
+		return nil, utils.NewCustomDatabaseError(err)
 	}
 
-	if user == nil {
-		span.SetStatus(codes.Error, "Email does not exist")
-		return nil, utils.NewCustomUserError("email not exist")
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = []byte(user.Password)
 	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(input.Password)); err != nil || user == nil {
+		u.recordLoginFailure(ctx, span, keys)
+		failedLogins.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", "invalid_credentials")))
 		span.SetStatus(codes.Error, "Invalid email or password")
-		return nil, utils.NewCustomUserError("invalid email or password")
+		return nil, utils.NewCustomAuthError(invalidCredentialsMessage)
+	}
+
+	// Only the account is forgiven: a valid login must not clear the count for an IP that is
+	// guessing passwords for other accounts.
+	if err := u.loginAttempts.Reset(ctx, keys[0].key); err != nil {
+		span.RecordError(err)
 	}
 
-	token, err := utils.GenerateJWT(user.ID, user.Email, config.LoadConfig().JWT.Secret, config.LoadConfig().JWT.Expiry)
+	token, refreshToken, err := u.issueTokens(ctx, user.ID, user.Email, user.Role, "")
 	if err != nil {
 		span.RecordError(err)
 		return nil, utils.NewCustomSystemError("System Error")
@@ -103,11 +164,385 @@ func (u *userUseCase) Login(ctx context.Context, input usecase.LoginInput) (*use
 
 	span.SetStatus(codes.Ok, "Login successful")
 	return &usecase.LoginOutput{
-		Token: token,
+		Token:        token,
+		RefreshToken: refreshToken,
 		User: usecase.User{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			Role:          user.Role,
+			EmailVerified: user.EmailVerifiedAt != nil,
 		},
 	}, nil
 }
+
+// Refresh exchanges a refresh token for a new access and refresh token pair. Each refresh token
+// can be used once; presenting a rotated token again revokes every token of that login.
+func (u *userUseCase) Refresh(ctx context.Context, input usecase.RefreshInput) (*usecase.RefreshOutput, utils.CustomError) {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.Refresh")
+	defer span.End()
+
+	stored, err := u.repo.RefreshTokenRepository().GetByHash(ctx, utils.HashToken(input.RefreshToken))
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if stored == nil || stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		span.SetStatus(codes.Error, "Invalid refresh token")
+		return nil, utils.NewCustomAuthError("invalid refresh token")
+	}
+	if stored.RotatedAt != nil {
+		return nil, u.handleReuse(ctx, span, stored)
+	}
+
+	user, err := u.repo.UserRepository().GetByID(ctx, stored.UserID)
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if user == nil {
+		span.SetStatus(codes.Error, "User no longer exists")
+		return nil, utils.NewCustomAuthError("invalid refresh token")
+	}
+
+	var output usecase.RefreshOutput
+	var reused bool
+	customErr := u.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		if err := u.repo.RefreshTokenRepository().Rotate(txCtx, stored.ID); err != nil {
+			if errors.Is(err, repository.ErrRefreshTokenRotated) {
+				reused = true
+				return utils.NewCustomAuthError("invalid refresh token")
+			}
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+
+		token, refreshToken, err := u.issueTokens(txCtx, user.ID, user.Email, user.Role, stored.FamilyID)
+		if err != nil {
+			span.RecordError(err)
+			return utils.NewCustomSystemError("System Error")
+		}
+
+		output = usecase.RefreshOutput{Token: token, RefreshToken: refreshToken}
+		return nil
+	})
+	if reused {
+		return nil, u.handleReuse(ctx, span, stored)
+	}
+	if customErr != nil {
+		span.RecordError(customErr)
+		return nil, customErr
+	}
+
+	span.SetStatus(codes.Ok, "Token refreshed successfully")
+	return &output, nil
+}
+
+// Logout revokes the access token used for the request and, when given, the refresh token
+// of the same login.
+func (u *userUseCase) Logout(ctx context.Context, input usecase.LogoutInput, accessToken usecase.AccessToken) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.Logout")
+	defer span.End()
+
+	if accessToken.ID != "" {
+		if err := u.denylist.Deny(ctx, accessToken.ID, accessToken.ExpiresAt); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomSystemError("System Error")
+		}
+	}
+
+	if input.RefreshToken == "" {
+		span.SetStatus(codes.Ok, "Logout successful")
+		return nil
+	}
+
+	stored, err := u.repo.RefreshTokenRepository().GetByHash(ctx, utils.HashToken(input.RefreshToken))
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if stored == nil || stored.UserID != accessToken.UserID {
+		span.SetStatus(codes.Ok, "Logout successful")
+		return nil
+	}
+
+	revoked, err := u.repo.RefreshTokenRepository().RevokeFamily(ctx, stored.FamilyID)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if err := u.denyAccessTokens(ctx, revoked); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	span.SetStatus(codes.Ok, "Logout successful")
+	return nil
+}
+
+// RevokeSessions revokes every refresh and access token of a user, for example when the
+// account has been compromised.
+func (u *userUseCase) RevokeSessions(ctx context.Context, userID int64) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.RevokeSessions")
+	defer span.End()
+
+	user, err := u.repo.UserRepository().GetByID(ctx, userID)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if user == nil {
+		return utils.NewCustomNotFoundError("User Not Found")
+	}
+
+	if err := u.revokeAllTokens(ctx, userID); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	span.SetStatus(codes.Ok, "Sessions revoked successfully")
+	return nil
+}
+
+// ForgotPassword emails a password reset token. Unknown addresses are silently ignored so that
+// the endpoint cannot be used to find out which emails are registered.
+func (u *userUseCase) ForgotPassword(ctx context.Context, input usecase.ForgotPasswordInput) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.ForgotPassword")
+	defer span.End()
+
+	user, err := u.repo.UserRepository().GetByEmail(ctx, input.Email)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if user == nil {
+		span.SetStatus(codes.Ok, "Email not registered")
+		return nil
+	}
+
+	// Only the most recent reset email should work.
+	if err := u.repo.UserTokenRepository().InvalidateByUserID(ctx, user.ID, repository.UserTokenPasswordReset); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+
+	token, err := u.createUserToken(ctx, user.ID, repository.UserTokenPasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	if err := u.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Someone asked to reset the password for your account.\n\n"+
+			"Use this token to choose a new password: %s\n\n"+
+			"It expires in %d minutes. If you did not ask for this, you can ignore this email.", token, int(passwordResetTokenTTL.Minutes())),
+	}); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	span.SetStatus(codes.Ok, "Password reset email sent")
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token and signs the user out everywhere.
+func (u *userUseCase) ResetPassword(ctx context.Context, input usecase.ResetPasswordInput) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.ResetPassword")
+	defer span.End()
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	var userID int64
+	customErr := u.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		token, customErr := u.consumeUserToken(txCtx, span, repository.UserTokenPasswordReset, input.Token)
+		if customErr != nil {
+			return customErr
+		}
+		userID = token.UserID
+
+		if err := u.repo.UserRepository().UpdatePassword(txCtx, userID, string(hashedPassword)); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+	if customErr != nil {
+		span.RecordError(customErr)
+		return customErr
+	}
+
+	if err := u.revokeAllTokens(ctx, userID); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	span.SetStatus(codes.Ok, "Password reset successfully")
+	return nil
+}
+
+// VerifyEmail confirms the user's email address using a verification token.
+func (u *userUseCase) VerifyEmail(ctx context.Context, input usecase.VerifyEmailInput) utils.CustomError {
+	ctx, span := trace.SpanFromContext(ctx).TracerProvider().Tracer("").Start(ctx, "userUseCase.VerifyEmail")
+	defer span.End()
+
+	customErr := u.repo.WithTransaction(ctx, nil, func(txCtx context.Context) utils.CustomError {
+		token, customErr := u.consumeUserToken(txCtx, span, repository.UserTokenEmailVerification, input.Token)
+		if customErr != nil {
+			return customErr
+		}
+
+		if err := u.repo.UserRepository().MarkEmailVerified(txCtx, token.UserID); err != nil {
+			span.RecordError(err)
+			return utils.NewCustomDatabaseError(err)
+		}
+		return nil
+	})
+	if customErr != nil {
+		span.RecordError(customErr)
+		return customErr
+	}
+
+	span.SetStatus(codes.Ok, "Email verified successfully")
+	return nil
+}
+
+// sendEmailVerification emails a new verification token to the user.
+func (u *userUseCase) sendEmailVerification(ctx context.Context, userID int64, email string) error {
+	token, err := u.createUserToken(ctx, userID, repository.UserTokenEmailVerification, emailVerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	return u.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Welcome to the bookstore!\n\n"+
+			"Use this token to verify your email address: %s\n\n"+
+			"It expires in %d hours.", token, int(emailVerificationTokenTTL.Hours())),
+	})
+}
+
+// createUserToken stores the hash of a new single-use token and returns the token itself.
+func (u *userUseCase) createUserToken(ctx context.Context, userID int64, purpose string, ttl time.Duration) (string, error) {
+	token, err := utils.NewRandomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := u.repo.UserTokenRepository().Create(ctx, &repository.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: utils.HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// consumeUserToken checks a single-use token and marks it used.
+func (u *userUseCase) consumeUserToken(ctx context.Context, span trace.Span, purpose, value string) (*repository.UserToken, utils.CustomError) {
+	token, err := u.repo.UserTokenRepository().GetByHash(ctx, purpose, utils.HashToken(value))
+	if err != nil {
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+	if token == nil || token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, utils.NewCustomUserError("invalid or expired token")
+	}
+
+	if err := u.repo.UserTokenRepository().MarkUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, repository.ErrUserTokenUsed) {
+			return nil, utils.NewCustomUserError("invalid or expired token")
+		}
+		span.RecordError(err)
+		return nil, utils.NewCustomDatabaseError(err)
+	}
+
+	return token, nil
+}
+
+// revokeAllTokens revokes every refresh token of a user and denies the access tokens paired with them.
+func (u *userUseCase) revokeAllTokens(ctx context.Context, userID int64) error {
+	revoked, err := u.repo.RefreshTokenRepository().RevokeByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return u.denyAccessTokens(ctx, revoked)
+}
+
+// issueTokens signs an access token and stores the refresh token paired with it. An empty
+// familyID starts a new token family, as on login.
+func (u *userUseCase) issueTokens(ctx context.Context, userID int64, email, role, familyID string) (string, string, error) {
+	jwtConfig := config.LoadConfig().JWT
+
+	accessToken, claims, err := utils.GenerateJWT(userID, email, role, jwtConfig.Secret, jwtConfig.Expiry)
+	if err != nil {
+		return "", "", err
+	}
+
+	refreshToken, err := utils.NewRandomToken(32)
+	if err != nil {
+		return "", "", err
+	}
+
+	if familyID == "" {
+		familyID, err = utils.NewRandomToken(16)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	if _, err := u.repo.RefreshTokenRepository().Create(ctx, &repository.RefreshToken{
+		UserID:          userID,
+		TokenHash:       utils.HashToken(refreshToken),
+		FamilyID:        familyID,
+		AccessTokenID:   claims.Id,
+		AccessExpiresAt: time.Unix(claims.ExpiresAt, 0),
+		ExpiresAt:       time.Now().Add(time.Duration(jwtConfig.RefreshExpiry) * time.Hour),
+	}); err != nil {
+		return "", "", err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// handleReuse revokes the whole family of a refresh token that was presented after rotation,
+// since either the client or an attacker holds a stolen copy.
+func (u *userUseCase) handleReuse(ctx context.Context, span trace.Span, stored *repository.RefreshToken) utils.CustomError {
+	span.AddEvent("refresh_token_reuse_detected")
+	span.SetStatus(codes.Error, "Refresh token reuse detected")
+
+	revoked, err := u.repo.RefreshTokenRepository().RevokeFamily(ctx, stored.FamilyID)
+	if err != nil {
+		span.RecordError(err)
+		return utils.NewCustomDatabaseError(err)
+	}
+	if err := u.denyAccessTokens(ctx, revoked); err != nil {
+		span.RecordError(err)
+		return utils.NewCustomSystemError("System Error")
+	}
+
+	return utils.NewCustomAuthError("invalid refresh token")
+}
+
+// denyAccessTokens puts the access tokens paired with the given refresh tokens on the denylist.
+func (u *userUseCase) denyAccessTokens(ctx context.Context, tokens []*repository.RefreshToken) error {
+	now := time.Now()
+	for _, token := range tokens {
+		if token.AccessExpiresAt.Before(now) {
+			continue
+		}
+		if err := u.denylist.Deny(ctx, token.AccessTokenID, token.AccessExpiresAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
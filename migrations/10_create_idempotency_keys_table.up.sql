@@ -0,0 +1,10 @@
+CREATE TABLE idempotency_keys (
+    user_id INT NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    -- 0 while the original request is still being processed
+    status_code INT NOT NULL DEFAULT 0,
+    response_body BYTEA,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, key)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE refresh_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    -- every token issued by rotating the same login shares a family
+    family_id VARCHAR(64) NOT NULL,
+    access_token_id VARCHAR(64) NOT NULL,
+    access_expires_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    rotated_at TIMESTAMP,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX idx_refresh_tokens_user_id ON refresh_tokens (user_id);
@@ -0,0 +1,14 @@
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+
+CREATE TABLE user_tokens (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    -- password_reset or email_verification
+    purpose VARCHAR(50) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id, purpose);
@@ -0,0 +1,12 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE books DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE books ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(author, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_books_search_vector ON books USING GIN (search_vector);
+-- Trigram indexes serve fuzzy matching on typos as well as the ILIKE title/author filters.
+CREATE INDEX IF NOT EXISTS idx_books_title_trgm ON books USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_books_author_trgm ON books USING GIN (author gin_trgm_ops);
@@ -0,0 +1,20 @@
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    -- NULL for top-level categories; a category with subcategories cannot be deleted
+    parent_id INT REFERENCES categories (id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    slug VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (parent_id <> id)
+);
+
+CREATE INDEX idx_categories_parent_id ON categories (parent_id);
+
+CREATE TABLE book_categories (
+    book_id INT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES categories (id) ON DELETE CASCADE,
+    PRIMARY KEY (book_id, category_id)
+);
+
+CREATE INDEX idx_book_categories_category_id ON book_categories (category_id);
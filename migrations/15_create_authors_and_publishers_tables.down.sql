@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS book_authors;
+
+DROP INDEX IF EXISTS idx_books_publisher_id;
+ALTER TABLE books DROP COLUMN IF EXISTS publisher_id;
+
+DROP TABLE IF EXISTS publishers;
+DROP TABLE IF EXISTS authors;
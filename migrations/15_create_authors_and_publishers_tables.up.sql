@@ -0,0 +1,37 @@
+-- name_key ignores case, spacing and punctuation, so "J.R.R. Tolkien" and "JRR Tolkien" are
+-- the same author.
+CREATE TABLE authors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    name_key TEXT GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (regexp_replace(name, '[^[:alnum:]]+', '', 'g') <> '')
+);
+
+CREATE INDEX idx_authors_name_trgm ON authors USING GIN (name gin_trgm_ops);
+
+CREATE TABLE publishers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    name_key TEXT GENERATED ALWAYS AS (lower(regexp_replace(name, '[^[:alnum:]]+', '', 'g'))) STORED UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (regexp_replace(name, '[^[:alnum:]]+', '', 'g') <> '')
+);
+
+ALTER TABLE books ADD COLUMN publisher_id INT REFERENCES publishers (id) ON DELETE SET NULL;
+
+CREATE INDEX idx_books_publisher_id ON books (publisher_id);
+
+-- books.author stays as the display credit line; book_authors links the people behind it.
+CREATE TABLE book_authors (
+    book_id INT NOT NULL REFERENCES books (id) ON DELETE CASCADE,
+    author_id INT NOT NULL REFERENCES authors (id) ON DELETE RESTRICT,
+    role VARCHAR(20) NOT NULL DEFAULT 'author' CHECK (role IN ('author', 'translator', 'editor')),
+    -- order of the contributor in the book's credits
+    position INT NOT NULL DEFAULT 1,
+    PRIMARY KEY (book_id, author_id, role)
+);
+
+CREATE INDEX idx_book_authors_author_id ON book_authors (author_id);
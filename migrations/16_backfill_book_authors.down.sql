@@ -0,0 +1,2 @@
+DELETE FROM book_authors;
+DELETE FROM authors;
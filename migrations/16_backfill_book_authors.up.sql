@@ -0,0 +1,21 @@
+-- Split every credit line on "&" and ";" into authors, keeping the first spelling of each
+-- name, then link the books to them in credit order. Publishers have no source to backfill from.
+INSERT INTO authors (name)
+SELECT DISTINCT ON (lower(regexp_replace(credit.name, '[^[:alnum:]]+', '', 'g'))) credit.name
+FROM (
+    SELECT b.id, btrim(s.name) AS name
+    FROM books b, regexp_split_to_table(b.author, '\s*[&;]\s*') AS s(name)
+) credit
+WHERE regexp_replace(credit.name, '[^[:alnum:]]+', '', 'g') <> ''
+ORDER BY lower(regexp_replace(credit.name, '[^[:alnum:]]+', '', 'g')), credit.id
+ON CONFLICT (name_key) DO NOTHING;
+
+INSERT INTO book_authors (book_id, author_id, role, position)
+SELECT credit.id, a.id, 'author', MIN(credit.position)
+FROM (
+    SELECT b.id, btrim(s.name) AS name, s.position
+    FROM books b, regexp_split_to_table(b.author, '\s*[&;]\s*') WITH ORDINALITY AS s(name, position)
+) credit
+JOIN authors a ON a.name_key = lower(regexp_replace(credit.name, '[^[:alnum:]]+', '', 'g'))
+GROUP BY credit.id, a.id
+ON CONFLICT DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE books ADD COLUMN stock INT NOT NULL DEFAULT 0 CHECK (stock >= 0);
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS orders_totals_immutable ON orders;
+DROP TRIGGER IF EXISTS order_items_immutable ON order_items;
+DROP FUNCTION IF EXISTS prevent_order_price_change();
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS subtotal,
+    DROP COLUMN IF EXISTS total;
+
+ALTER TABLE order_items
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS unit_price;
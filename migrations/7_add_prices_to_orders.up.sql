@@ -0,0 +1,43 @@
+ALTER TABLE order_items
+    ADD COLUMN title VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN unit_price DECIMAL(10, 2) NOT NULL DEFAULT 0;
+
+ALTER TABLE orders
+    ADD COLUMN subtotal DECIMAL(12, 2) NOT NULL DEFAULT 0,
+    ADD COLUMN total DECIMAL(12, 2) NOT NULL DEFAULT 0;
+
+-- Backfill existing orders with the current book prices, the best information available.
+UPDATE order_items oi
+SET title = b.title, unit_price = b.price
+FROM books b
+WHERE b.id = oi.book_id;
+
+UPDATE orders o
+SET subtotal = t.amount, total = t.amount
+FROM (
+    SELECT order_id, SUM(unit_price * quantity) AS amount
+    FROM order_items
+    GROUP BY order_id
+) t
+WHERE t.order_id = o.id;
+
+-- Priced order lines and order totals are part of the audit trail and must never change.
+CREATE OR REPLACE FUNCTION prevent_order_price_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_TABLE_NAME = 'order_items' THEN
+        RAISE EXCEPTION 'order_items are immutable';
+    END IF;
+    IF NEW.subtotal IS DISTINCT FROM OLD.subtotal OR NEW.total IS DISTINCT FROM OLD.total THEN
+        RAISE EXCEPTION 'order totals are immutable';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_items_immutable
+    BEFORE UPDATE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION prevent_order_price_change();
+
+CREATE TRIGGER orders_totals_immutable
+    BEFORE UPDATE ON orders
+    FOR EACH ROW EXECUTE FUNCTION prevent_order_price_change();
@@ -0,0 +1,17 @@
+CREATE TABLE order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INT NOT NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    changed_by INT,
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history (order_id);
+
+-- Orders used to be written as "success" once placed, which is what "paid" means now.
+UPDATE orders SET status = 'paid' WHERE status = 'success';
+
+INSERT INTO order_status_history (order_id, from_status, to_status, note, created_at)
+SELECT id, NULL, status, 'backfilled', created_at FROM orders;
@@ -3,18 +3,68 @@ package utils
 import (
 	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 )
 
-const (
-	TransactionContextKey = "DB-TRX"
-)
+type transactionContextKey struct{}
+
+// transaction is the context value for an open transaction. depth counts the savepoints
+// opened inside it; hooks is shared by every savepoint of the transaction.
+type transaction struct {
+	tx    *sql.Tx
+	depth int
+	hooks *[]func(context.Context)
+}
+
+// ContextWithTransaction returns a copy of ctx in which the query helpers run on tx.
+func ContextWithTransaction(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, transactionContextKey{}, transaction{tx: tx, hooks: new([]func(context.Context))})
+}
+
+// AfterCommit defers fn until the transaction in ctx commits, or runs it right away when ctx
+// carries no transaction. Hooks of a transaction that rolls back are dropped.
+func AfterCommit(ctx context.Context, fn func(context.Context)) {
+	t, ok := ctx.Value(transactionContextKey{}).(transaction)
+	if !ok || t.hooks == nil {
+		fn(ctx)
+		return
+	}
+	*t.hooks = append(*t.hooks, fn)
+}
+
+// RunCommitHooks runs the AfterCommit hooks registered on the transaction in txCtx, in order,
+// with ctx. Call it once the transaction has committed.
+func RunCommitHooks(txCtx, ctx context.Context) {
+	t, ok := txCtx.Value(transactionContextKey{}).(transaction)
+	if !ok || t.hooks == nil {
+		return
+	}
+	for _, fn := range *t.hooks {
+		fn(ctx)
+	}
+}
+
+// TransactionFromContext returns the transaction stored in ctx, if any.
+func TransactionFromContext(ctx context.Context) (*sql.Tx, bool) {
+	t, ok := ctx.Value(transactionContextKey{}).(transaction)
+	return t.tx, ok
+}
+
+// ContextWithSavepoint returns a copy of ctx one savepoint deeper in its transaction, along
+// with a savepoint name that is unique within that transaction. ctx must carry a transaction.
+func ContextWithSavepoint(ctx context.Context) (context.Context, string) {
+	t, _ := ctx.Value(transactionContextKey{}).(transaction)
+	t.depth++
+	return context.WithValue(ctx, transactionContextKey{}, t), fmt.Sprintf("sp_%d", t.depth)
+}
 
 // ExecContextWithPreparedReturningID executes a prepared statement that returns a single ID using
-// a transaction from the context if available, or the database otherwise.
+// a transaction from the context if available, or the database otherwise. Failures are logged
+// with the default slog logger.
 func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query string, args ...interface{}) (int64, error) {
 	// Check if there's an active transaction in the context
-	tx, ok := ctx.Value(TransactionContextKey).(*sql.Tx)
+	tx, ok := TransactionFromContext(ctx)
 	var stmt *sql.Stmt
 	var err error
 
@@ -22,7 +72,7 @@ func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query s
 		// Prepare statement on the transaction
 		stmt, err = tx.PrepareContext(ctx, query)
 		if err != nil {
-			log.Printf("Transaction preparation error: %v", err)
+			slog.ErrorContext(ctx, "Transaction preparation error", slog.Any("error", err))
 			return 0, err
 		}
 		defer stmt.Close()
@@ -30,7 +80,7 @@ func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query s
 		var id int64
 		err = stmt.QueryRowContext(ctx, args...).Scan(&id)
 		if err != nil {
-			log.Printf("Transaction query execution error: %v", err)
+			slog.ErrorContext(ctx, "Transaction query execution error", slog.Any("error", err))
 			return 0, err
 		}
 		return id, nil
@@ -39,7 +89,7 @@ func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query s
 	// Prepare the statement on the regular DB connection
 	stmt, err = db.PrepareContext(ctx, query)
 	if err != nil {
-		log.Printf("DB preparation error: %v", err)
+		slog.ErrorContext(ctx, "DB preparation error", slog.Any("error", err))
 		return 0, err
 	}
 	defer stmt.Close()
@@ -47,7 +97,7 @@ func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query s
 	var id int64
 	err = stmt.QueryRowContext(ctx, args...).Scan(&id)
 	if err != nil {
-		log.Printf("DB query execution error: %v", err)
+		slog.ErrorContext(ctx, "DB query execution error", slog.Any("error", err))
 		return 0, err
 	}
 
@@ -56,7 +106,7 @@ func ExecContextWithPreparedReturningID(ctx context.Context, db *sql.DB, query s
 
 // prepareAndQueryRowContext prepares a statement with transaction support and executes QueryRowContext.
 func PrepareAndQueryRowContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) *sql.Row {
-	tx, ok := ctx.Value(TransactionContextKey).(*sql.Tx)
+	tx, ok := TransactionFromContext(ctx)
 	if ok {
 		stmt, err := tx.PrepareContext(ctx, query)
 		if err != nil {
@@ -75,9 +125,19 @@ func PrepareAndQueryRowContext(ctx context.Context, db *sql.DB, query string, ar
 	return stmt.QueryRowContext(ctx, args...)
 }
 
+// QueryRowContext runs a query that returns at most one row, on the transaction from the context
+// if available, or the database otherwise. Unlike PrepareAndQueryRowContext it never returns
+// nil: any failure, including an expired context, is reported by Scan.
+func QueryRowContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) *sql.Row {
+	if tx, ok := TransactionFromContext(ctx); ok {
+		return tx.QueryRowContext(ctx, query, args...)
+	}
+	return db.QueryRowContext(ctx, query, args...)
+}
+
 // prepareAndQueryContext prepares a statement with transaction support and executes QueryContext.
 func PrepareAndQueryContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (*sql.Rows, error) {
-	tx, ok := ctx.Value(TransactionContextKey).(*sql.Tx)
+	tx, ok := TransactionFromContext(ctx)
 	if ok {
 		stmt, err := tx.PrepareContext(ctx, query)
 		if err != nil {
@@ -95,3 +155,24 @@ func PrepareAndQueryContext(ctx context.Context, db *sql.DB, query string, args
 
 	return stmt.QueryContext(ctx, args...)
 }
+
+// PrepareAndExecContext prepares a statement with transaction support and executes ExecContext.
+func PrepareAndExecContext(ctx context.Context, db *sql.DB, query string, args ...interface{}) (sql.Result, error) {
+	tx, ok := TransactionFromContext(ctx)
+	if ok {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		defer stmt.Close()
+		return stmt.ExecContext(ctx, args...)
+	}
+
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	return stmt.ExecContext(ctx, args...)
+}
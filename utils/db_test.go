@@ -3,6 +3,7 @@ package utils
 import (
 	"context"
 	"log"
+	"regexp"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -45,7 +46,7 @@ func TestExecContextWithPreparedReturningID(t *testing.T) {
 	}
 	defer tx.Rollback()
 
-	ctxWithTx := context.WithValue(ctx, TransactionContextKey, tx)
+	ctxWithTx := ContextWithTransaction(ctx, tx)
 
 	log.Printf("Preparing SQL for transaction: %s", query)
 
@@ -102,7 +103,7 @@ func TestPrepareAndQueryRowContext(t *testing.T) {
 	}
 	defer tx.Rollback()
 
-	ctxWithTx := context.WithValue(ctx, TransactionContextKey, tx)
+	ctxWithTx := ContextWithTransaction(ctx, tx)
 
 	mock.ExpectPrepare(query).
 		ExpectQuery().
@@ -160,7 +161,7 @@ func TestPrepareAndQueryContext(t *testing.T) {
 	}
 	defer tx.Rollback()
 
-	ctxWithTx := context.WithValue(ctx, TransactionContextKey, tx)
+	ctxWithTx := ContextWithTransaction(ctx, tx)
 
 	mock.ExpectPrepare(query).
 		ExpectQuery().
@@ -181,3 +182,82 @@ func TestPrepareAndQueryContext(t *testing.T) {
 		t.Errorf("there were unfulfilled expectations: %s", err)
 	}
 }
+
+func TestPrepareAndExecContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	query := "UPDATE test_table SET name = $1 WHERE id = $2"
+	ctx := context.Background()
+
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("test-name", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err := PrepareAndExecContext(ctx, db, query, "test-name", 1)
+	assert.NoError(t, err)
+	affected, _ := result.RowsAffected()
+	assert.Equal(t, int64(1), affected)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	ctxWithTx := ContextWithTransaction(ctx, tx)
+
+	mock.ExpectPrepare(regexp.QuoteMeta(query)).
+		ExpectExec().
+		WithArgs("test-name", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	result, err = PrepareAndExecContext(ctxWithTx, db, query, "test-name", 1)
+	assert.NoError(t, err)
+	affected, _ = result.RowsAffected()
+	assert.Equal(t, int64(1), affected)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestContextWithSavepoint(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to open sqlmock: %v", err)
+	}
+	defer db.Close()
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback()
+
+	_, ok := TransactionFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx := ContextWithTransaction(context.Background(), tx)
+	ctx, first := ContextWithSavepoint(ctx)
+	nested, second := ContextWithSavepoint(ctx)
+	_, sibling := ContextWithSavepoint(ctx)
+
+	assert.Equal(t, "sp_1", first)
+	assert.Equal(t, "sp_2", second)
+	assert.Equal(t, "sp_2", sibling)
+
+	got, ok := TransactionFromContext(nested)
+	assert.True(t, ok)
+	assert.Same(t, tx, got)
+}
@@ -1,27 +1,55 @@
 package utils
 
+import (
+	"context"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// pqQueryCanceled is the Postgres error code for a statement canceled by a deadline, either the
+// driver's cancel request when the context expires or the server's statement_timeout.
+const pqQueryCanceled pq.ErrorCode = "57014"
+
 type ErrorType string
 
 const (
-	UserError   ErrorType = "USER_ERROR"
-	SystemError ErrorType = "SYSTEM_ERROR"
+	UserError            ErrorType = "USER_ERROR"
+	SystemError          ErrorType = "SYSTEM_ERROR"
+	NotFoundError        ErrorType = "NOT_FOUND_ERROR"
+	ConflictError        ErrorType = "CONFLICT_ERROR"
+	AuthError            ErrorType = "AUTH_ERROR"
+	TooManyRequestsError ErrorType = "TOO_MANY_REQUESTS_ERROR"
+	TimeoutError         ErrorType = "TIMEOUT_ERROR"
 )
 
 type customError struct {
 	Type    ErrorType
 	Message string
+	cause   error
 }
 
 type CustomError interface {
 	Error() string
 	IsUserError() bool
 	IsSystemError() bool
+	IsNotFoundError() bool
+	IsConflictError() bool
+	IsAuthError() bool
+	IsTooManyRequestsError() bool
+	IsTimeoutError() bool
 }
 
 func (e *customError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the underlying error, if any, so that callers can inspect it with errors.Is
+// and errors.As without exposing it in the message.
+func (e *customError) Unwrap() error {
+	return e.cause
+}
+
 // NewCustomUserError creates a new user error with a message
 func NewCustomUserError(message string) *customError {
 	return &customError{
@@ -38,6 +66,59 @@ func NewCustomSystemError(message string) *customError {
 	}
 }
 
+// NewCustomNotFoundError creates a new not found error with a message
+func NewCustomNotFoundError(message string) *customError {
+	return &customError{
+		Type:    NotFoundError,
+		Message: message,
+	}
+}
+
+// NewCustomConflictError creates a new conflict error with a message
+func NewCustomConflictError(message string) *customError {
+	return &customError{
+		Type:    ConflictError,
+		Message: message,
+	}
+}
+
+// NewCustomAuthError creates a new authentication error with a message
+func NewCustomAuthError(message string) *customError {
+	return &customError{
+		Type:    AuthError,
+		Message: message,
+	}
+}
+
+// NewCustomTooManyRequestsError creates a new error for a client that has to back off
+func NewCustomTooManyRequestsError(message string) *customError {
+	return &customError{
+		Type:    TooManyRequestsError,
+		Message: message,
+	}
+}
+
+// NewCustomTimeoutError creates a new error for an operation that ran out of time
+func NewCustomTimeoutError(message string) *customError {
+	return &customError{
+		Type:    TimeoutError,
+		Message: message,
+	}
+}
+
+// NewCustomDatabaseError converts a repository error into a custom error, reporting an
+// exceeded deadline or a statement canceled mid-query as a timeout rather than a generic
+// database failure. The repository error is kept as the cause.
+func NewCustomDatabaseError(err error) *customError {
+	customErr := NewCustomSystemError("Database Error")
+	var pqErr *pq.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &pqErr) && pqErr.Code == pqQueryCanceled) {
+		customErr = NewCustomTimeoutError("Database Timeout")
+	}
+	customErr.cause = err
+	return customErr
+}
+
 // IsUserError method checks if the error is of type USER_ERROR
 func (e *customError) IsUserError() bool {
 	return e.Type == UserError
@@ -47,3 +128,28 @@ func (e *customError) IsUserError() bool {
 func (e *customError) IsSystemError() bool {
 	return e.Type == SystemError
 }
+
+// IsNotFoundError method checks if the error is of type NOT_FOUND_ERROR
+func (e *customError) IsNotFoundError() bool {
+	return e.Type == NotFoundError
+}
+
+// IsConflictError method checks if the error is of type CONFLICT_ERROR
+func (e *customError) IsConflictError() bool {
+	return e.Type == ConflictError
+}
+
+// IsAuthError method checks if the error is of type AUTH_ERROR
+func (e *customError) IsAuthError() bool {
+	return e.Type == AuthError
+}
+
+// IsTooManyRequestsError method checks if the error is of type TOO_MANY_REQUESTS_ERROR
+func (e *customError) IsTooManyRequestsError() bool {
+	return e.Type == TooManyRequestsError
+}
+
+// IsTimeoutError method checks if the error is of type TIMEOUT_ERROR
+func (e *customError) IsTimeoutError() bool {
+	return e.Type == TimeoutError
+}
@@ -1,8 +1,12 @@
 package utils
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
 
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +38,82 @@ func TestCustomError_ErrorMethod(t *testing.T) {
 
 	assert.Equal(t, message, err.Error())
 }
+
+func TestNewCustomNotFoundError(t *testing.T) {
+	message := "This is a not found error"
+	err := NewCustomNotFoundError(message)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, NotFoundError, err.Type)
+	assert.Equal(t, message, err.Message)
+	assert.True(t, err.IsNotFoundError())
+	assert.False(t, err.IsUserError())
+	assert.False(t, err.IsSystemError())
+}
+
+func TestNewCustomConflictError(t *testing.T) {
+	message := "This is a conflict error"
+	err := NewCustomConflictError(message)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, ConflictError, err.Type)
+	assert.Equal(t, message, err.Message)
+	assert.True(t, err.IsConflictError())
+	assert.False(t, err.IsUserError())
+	assert.False(t, err.IsNotFoundError())
+}
+
+func TestNewCustomAuthError(t *testing.T) {
+	message := "This is an auth error"
+	err := NewCustomAuthError(message)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, AuthError, err.Type)
+	assert.Equal(t, message, err.Message)
+	assert.True(t, err.IsAuthError())
+	assert.False(t, err.IsUserError())
+	assert.False(t, err.IsSystemError())
+}
+
+func TestNewCustomTooManyRequestsError(t *testing.T) {
+	message := "This is a too many requests error"
+	err := NewCustomTooManyRequestsError(message)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, TooManyRequestsError, err.Type)
+	assert.Equal(t, message, err.Message)
+	assert.True(t, err.IsTooManyRequestsError())
+	assert.False(t, err.IsUserError())
+	assert.False(t, err.IsAuthError())
+}
+
+func TestNewCustomTimeoutError(t *testing.T) {
+	message := "This is a timeout error"
+	err := NewCustomTimeoutError(message)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, TimeoutError, err.Type)
+	assert.Equal(t, message, err.Message)
+	assert.True(t, err.IsTimeoutError())
+	assert.False(t, err.IsSystemError())
+	assert.False(t, err.IsUserError())
+}
+
+func TestNewCustomDatabaseError(t *testing.T) {
+	err := NewCustomDatabaseError(fmt.Errorf("query books: %w", context.DeadlineExceeded))
+	assert.True(t, err.IsTimeoutError())
+	assert.Equal(t, "Database Timeout", err.Error())
+
+	// lib/pq reports a query canceled by an expired context as a Postgres error, not as the
+	// context's error.
+	canceled := &pq.Error{Code: "57014", Message: "canceling statement due to user request"}
+	err = NewCustomDatabaseError(fmt.Errorf("query books: %w", canceled))
+	assert.True(t, err.IsTimeoutError())
+	assert.ErrorIs(t, err, canceled)
+
+	cause := errors.New("connection refused")
+	err = NewCustomDatabaseError(cause)
+	assert.True(t, err.IsSystemError())
+	assert.Equal(t, "Database Error", err.Error())
+	assert.ErrorIs(t, err, cause)
+}
@@ -9,21 +9,36 @@ import (
 type Claims struct {
 	UserID int64
 	Email  string
+	Role   string
 	jwt.StandardClaims
 }
 
-// GenerateJWT generates a JWT token for the given user ID and email.
-func GenerateJWT(userID int64, email, secret string, expiryHours int) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(expiryHours) * time.Hour)
+// GenerateJWT generates a JWT token for the given user ID, email and role. Each token gets a
+// unique ID (the jti claim) so that it can be revoked before it expires.
+func GenerateJWT(userID int64, email, role, secret string, expiryHours int) (string, *Claims, error) {
+	tokenID, err := NewRandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	expirationTime := now.Add(time.Duration(expiryHours) * time.Hour)
 
 	claims := &Claims{
 		UserID: userID,
 		Email:  email,
+		Role:   role,
 		StandardClaims: jwt.StandardClaims{
+			Id:        tokenID,
+			IssuedAt:  now.Unix(),
 			ExpiresAt: expirationTime.Unix(),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
@@ -11,13 +11,15 @@ import (
 func TestGenerateJWT(t *testing.T) {
 	userID := int64(123)
 	email := "satrio@gmail.com"
+	role := "admin"
 	secret := "test_secret"
 	expiryHours := 24
 
-	token, err := GenerateJWT(userID, email, secret, expiryHours)
+	token, generated, err := GenerateJWT(userID, email, role, secret, expiryHours)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, generated.Id)
 
 	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
 
@@ -33,6 +35,8 @@ func TestGenerateJWT(t *testing.T) {
 	if claims, ok := parsedToken.Claims.(jwt.MapClaims); ok && parsedToken.Valid {
 		assert.Equal(t, userID, int64(claims["UserID"].(float64)))
 		assert.Equal(t, email, claims["Email"].(string))
+		assert.Equal(t, role, claims["Role"].(string))
+		assert.Equal(t, generated.Id, claims["jti"].(string))
 		assert.Equal(t, time.Now().Add(time.Duration(expiryHours)*time.Hour).Unix(), int64(claims["exp"].(float64)))
 	} else {
 		t.Fatal("Claims are not valid")
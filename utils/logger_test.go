@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewLogger(&buf, "info")
+	assert.NoError(t, err)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext)
+	ctx = ContextWithRequestID(ctx, "req-1")
+
+	logger.DebugContext(ctx, "hidden")
+	logger.InfoContext(ctx, "shown", "key", "value")
+
+	var record map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "shown", record["msg"])
+	assert.Equal(t, "value", record["key"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, spanContext.TraceID().String(), record["trace_id"])
+	assert.Equal(t, spanContext.SpanID().String(), record["span_id"])
+}
+
+func TestParseLogLevel(t *testing.T) {
+	_, err := ParseLogLevel("DEBUG")
+	assert.NoError(t, err)
+
+	_, err = ParseLogLevel("verbose")
+	assert.Error(t, err)
+}
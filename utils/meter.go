@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/masatrio/bookstore-api/config"
+)
+
+// NewMeterProvider creates a meter provider for the configured exporter and installs it as the
+// global provider. With the Prometheus exporter, the returned handler serves the scrape page;
+// with OTLP, metrics are pushed to the collector and the handler is nil, using the same headers
+// and TLS settings as the trace exporter in otlp.
+func NewMeterProvider(ctx context.Context, serviceName string, cfg config.MetricsConfig, otlp config.TracingConfig) (*metric.MeterProvider, http.Handler, error) {
+	res, err := resource.New(
+		ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+		),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var reader metric.Reader
+	var handler http.Handler
+	switch cfg.Exporter {
+	case "prometheus":
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+		exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = exporter
+		handler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	case "otlp":
+		tlsConfig, err := newOTLPTLSConfig(otlp)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(cfg.OTLPEndpoint),
+			otlpmetricgrpc.WithHeaders(otlp.Headers),
+		}
+		if tlsConfig == nil {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+
+		exporter, err := otlpmetricgrpc.New(ctx, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		reader = metric.NewPeriodicReader(exporter)
+	default:
+		return nil, nil, fmt.Errorf("unknown metrics exporter %q", cfg.Exporter)
+	}
+
+	mp := metric.NewMeterProvider(
+		metric.WithReader(reader),
+		metric.WithResource(res),
+	)
+
+	otel.SetMeterProvider(mp)
+
+	return mp, handler, nil
+}
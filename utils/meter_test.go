@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+
+	"github.com/masatrio/bookstore-api/config"
+)
+
+func TestNewMeterProviderPrometheus(t *testing.T) {
+	ctx := context.Background()
+	mp, handler, err := NewMeterProvider(ctx, "test-service", config.MetricsConfig{Exporter: "prometheus"}, config.TracingConfig{})
+	assert.NoError(t, err)
+	assert.NotNil(t, handler)
+	defer mp.Shutdown(ctx)
+
+	counter, err := otel.Meter("test").Int64Counter("bookstore.test.events")
+	assert.NoError(t, err)
+	counter.Add(ctx, 3)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), "bookstore_test_events_total")
+}
+
+func TestNewMeterProviderUnknownExporter(t *testing.T) {
+	_, _, err := NewMeterProvider(context.Background(), "test-service", config.MetricsConfig{Exporter: "statsd"}, config.TracingConfig{})
+	assert.Error(t, err)
+}
+
+func TestNewMeterProviderOTLPUsesTLSSettings(t *testing.T) {
+	// A missing CA file shows that the metrics exporter loads the shared TLS settings.
+	_, _, err := NewMeterProvider(context.Background(), "test-service",
+		config.MetricsConfig{Exporter: "otlp", OTLPEndpoint: "localhost:4317"},
+		config.TracingConfig{CAFile: "/nonexistent/ca.pem"})
+	assert.ErrorContains(t, err, "OTLP CA file")
+}
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRandomToken(t *testing.T) {
+	first, err := NewRandomToken(32)
+	assert.NoError(t, err)
+	second, err := NewRandomToken(32)
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 43)
+	assert.NotEqual(t, first, second)
+}
+
+func TestHashToken(t *testing.T) {
+	assert.Equal(t, HashToken("token"), HashToken("token"))
+	assert.NotEqual(t, HashToken("token"), HashToken("other"))
+	assert.Len(t, HashToken("token"), 64)
+}
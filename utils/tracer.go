@@ -2,30 +2,50 @@ package utils
 
 import (
 	"context"
-	"log"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	tracer "go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/masatrio/bookstore-api/config"
 )
 
-// NewTracer creates and returns a new OpenTelemetry tracer.
-func NewTracer(ctx context.Context, serviceName string) tracer.Tracer {
-	exporter, err := otlptrace.New(
-		ctx,
-		otlptracegrpc.NewClient(
-			otlptracegrpc.WithEndpoint("otel-collector:4317"),
-			otlptracegrpc.WithInsecure(),
-		),
-	)
+// NewTracer creates an OpenTelemetry tracer that exports spans over OTLP and installs its
+// provider and the W3C TraceContext and Baggage propagators globally. When tracing is disabled,
+// a no-op tracer is returned, but incoming trace context is still propagated. The returned
+// function flushes pending spans and must be called on shutdown.
+func NewTracer(ctx context.Context, serviceName string, enabled bool, cfg config.TracingConfig) (tracer.Tracer, func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !enabled {
+		tp := noop.NewTracerProvider()
+		otel.SetTracerProvider(tp)
+		return tp.Tracer(serviceName), func(context.Context) error { return nil }, nil
+	}
 
+	client, err := newTraceClient(cfg)
 	if err != nil {
-		log.Printf("failed to create OTLP trace exporter: %v", err)
-		return nil
+		return nil, nil, err
+	}
+
+	exporter, err := otlptrace.New(ctx, client)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
 	}
 
 	res, err := resource.New(
@@ -35,15 +55,73 @@ func NewTracer(ctx context.Context, serviceName string) tracer.Tracer {
 		),
 	)
 	if err != nil {
-		log.Fatalf("failed to create resource: %v", err)
+		return nil, nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
 	tp := trace.NewTracerProvider(
 		trace.WithBatcher(exporter),
 		trace.WithResource(res),
+		// Follow the caller's sampling decision so that traces are not cut in half.
+		trace.WithSampler(trace.ParentBased(trace.TraceIDRatioBased(cfg.SampleRatio))),
 	)
 
 	otel.SetTracerProvider(tp)
 
-	return tp.Tracer(serviceName)
+	return tp.Tracer(serviceName), tp.Shutdown, nil
+}
+
+// newTraceClient creates the OTLP client for the configured protocol.
+func newTraceClient(cfg config.TracingConfig) (otlptrace.Client, error) {
+	tlsConfig, err := newOTLPTLSConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Protocol {
+	case "grpc":
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(cfg.Endpoint),
+			otlptracegrpc.WithHeaders(cfg.Headers),
+		}
+		if tlsConfig == nil {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		return otlptracegrpc.NewClient(opts...), nil
+	case "http":
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(cfg.Endpoint),
+			otlptracehttp.WithHeaders(cfg.Headers),
+		}
+		if tlsConfig == nil {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsConfig))
+		}
+		return otlptracehttp.NewClient(opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %q", cfg.Protocol)
+	}
+}
+
+// newOTLPTLSConfig returns the TLS settings for connecting to the OTLP receiver, or nil when
+// cfg.Insecure is set.
+func newOTLPTLSConfig(cfg config.TracingConfig) (*tls.Config, error) {
+	if cfg.Insecure {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OTLP CA file: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in OTLP CA file %s", cfg.CAFile)
+		}
+	}
+	return tlsConfig, nil
 }
@@ -4,28 +4,53 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/masatrio/bookstore-api/config"
 )
 
 func TestNewTracer(t *testing.T) {
 	ctx := context.Background()
 
-	tracer := NewTracer(ctx, "test-service")
+	tracer, shutdown, err := NewTracer(ctx, "test-service", true, config.TracingConfig{
+		Endpoint:    "localhost:4317",
+		Protocol:    "grpc",
+		Insecure:    true,
+		SampleRatio: 1,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
 
 	if tracer == nil {
 		t.Fatalf("expected tracer to be non-nil, got nil")
 	}
 
 	provider := otel.GetTracerProvider()
-	if provider == nil {
-		t.Fatalf("expected tracer provider to be set, got nil")
+	if _, ok := provider.(*trace.TracerProvider); !ok {
+		t.Fatalf("expected SDK tracer provider to be set, got %T", provider)
 	}
 
-	if tp, ok := provider.(*trace.TracerProvider); ok {
-		err := tp.Shutdown(ctx)
-		if err != nil {
-			t.Errorf("failed to shut down tracer provider: %v", err)
-		}
+	if err := shutdown(ctx); err != nil {
+		t.Errorf("failed to shut down tracer provider: %v", err)
 	}
+
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
+
+func TestNewTracerDisabled(t *testing.T) {
+	tracer, shutdown, err := NewTracer(context.Background(), "test-service", false, config.TracingConfig{})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, tracer)
+	assert.IsType(t, noop.TracerProvider{}, otel.GetTracerProvider())
+	assert.NoError(t, shutdown(context.Background()))
+}
+
+func TestNewTracerUnknownProtocol(t *testing.T) {
+	_, _, err := NewTracer(context.Background(), "test-service", true, config.TracingConfig{Protocol: "udp", Insecure: true})
+	assert.Error(t, err)
 }